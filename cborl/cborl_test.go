@@ -19,10 +19,15 @@ package cborl
 
 import (
 	"bytes"
+	"errors"
+	"math"
+	"math/big"
 	"testing"
+	"time"
 
 	structform "github.com/elastic/go-structform"
 	"github.com/elastic/go-structform/sftest"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEncParseConsistent(t *testing.T) {
@@ -49,6 +54,19 @@ func TestEncParseBytesConsistent(t *testing.T) {
 	})
 }
 
+func TestCanonicalEncParseConsistent(t *testing.T) {
+	sftest.TestEncodeParseConsistent(t, sftest.Samples,
+		func() (structform.Visitor, func(structform.Visitor) error) {
+			buf := bytes.NewBuffer(nil)
+			vs := NewVisitor(buf)
+			vs.SetCanonical(true)
+
+			return vs, func(to structform.Visitor) error {
+				return Parse(buf.Bytes(), to)
+			}
+		})
+}
+
 func testEncParseConsistent(
 	t *testing.T,
 	parse func([]byte, structform.Visitor) error,
@@ -63,3 +81,338 @@ func testEncParseConsistent(
 			}
 		})
 }
+
+type extRecording struct {
+	sftest.Recording
+	tags   []uint64
+	times  []time.Time
+	bigs   []*big.Int
+	undefs int
+}
+
+func (r *extRecording) OnTag(tag uint64) error    { r.tags = append(r.tags, tag); return nil }
+func (r *extRecording) OnUndefined() error        { r.undefs++; return nil }
+func (r *extRecording) OnBigInt(i *big.Int) error { r.bigs = append(r.bigs, i); return nil }
+func (r *extRecording) OnTime(t time.Time) error  { r.times = append(r.times, t); return nil }
+
+type tagRecording struct {
+	sftest.Recording
+	tags []uint64
+}
+
+func (r *tagRecording) OnTag(tag uint64) error { r.tags = append(r.tags, tag); return nil }
+
+func TestParseHalfFloat(t *testing.T) {
+	tests := map[string]struct {
+		in       []byte
+		expected float32
+	}{
+		"one":        {[]byte{0xf9, 0x3c, 0x00}, 1},
+		"1.5":        {[]byte{0xf9, 0x3e, 0x00}, 1.5},
+		"max":        {[]byte{0xf9, 0x7b, 0xff}, 65504},
+		"negative":   {[]byte{0xf9, 0xc4, 0x00}, -4},
+		"subnormal":  {[]byte{0xf9, 0x00, 0x01}, 5.960464477539063e-8},
+		"min normal": {[]byte{0xf9, 0x04, 0x00}, 6.103515625e-05},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rec sftest.Recording
+			if err := Parse(test.in, &rec); err != nil {
+				t.Fatal(err)
+			}
+			rec.Assert(t, sftest.Recording{sftest.Float32Rec{test.expected}})
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	// tag 32 (URI) and 1 (epoch)
+	in := []byte{0x82, 0xd8, 0x20, 0x63, 'a', ':', 'b', 0xc1, 0x01}
+
+	t.Run("ignore tags", func(t *testing.T) {
+		var rec sftest.Recording
+		if err := Parse(in, &rec); err != nil {
+			t.Fatal(err)
+		}
+		rec.Assert(t, sftest.Recording(sftest.Arr(2, structform.AnyType,
+			sftest.StringRec{"a:b"}, sftest.Uint8Rec{1})))
+	})
+
+	t.Run("report tags", func(t *testing.T) {
+		var rec tagRecording
+		if err := Parse(in, &rec); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []uint64{32, 1}, rec.tags)
+	})
+
+	t.Run("report time", func(t *testing.T) {
+		var rec extRecording
+		if err := Parse(in, &rec); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []uint64{32}, rec.tags)
+		assert.Equal(t, []time.Time{time.Unix(1, 0).UTC()}, rec.times)
+	})
+}
+
+func TestParseTaggedMapValues(t *testing.T) {
+	tests := map[string][]byte{
+		// {"a": 1(0), "b": 2}
+		"1 byte tag": {0xa2, 0x61, 'a', 0xc1, 0x00, 0x61, 'b', 0x02},
+		// {"a": 256(0), "b": 2}
+		"2 byte tag": {0xa2, 0x61, 'a', 0xd9, 0x01, 0x00, 0x00, 0x61, 'b', 0x02},
+		// {_ "a": 1(0), "b": 2}
+		"indefinite map": {0xbf, 0x61, 'a', 0xc1, 0x00, 0x61, 'b', 0x02, 0xff},
+	}
+
+	for name, in := range tests {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			expected := sftest.Recording(sftest.Obj(2, structform.AnyType,
+				"a", sftest.Uint8Rec{0},
+				"b", sftest.Uint8Rec{2},
+			))
+			if in[0] == 0xbf {
+				expected = sftest.Recording(sftest.Obj(-1, structform.AnyType,
+					"a", sftest.Uint8Rec{0},
+					"b", sftest.Uint8Rec{2},
+				))
+			}
+
+			var rec sftest.Recording
+			if err := Parse(in, &rec); err != nil {
+				t.Fatal(err)
+			}
+			rec.Assert(t, expected)
+
+			// feed byte by byte, such that tags are split from their content
+			rec = nil
+			p := NewParser(&rec)
+			for _, b := range in {
+				if err := p.feed([]byte{b}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rec.Assert(t, expected)
+		})
+	}
+
+	t.Run("nested array", func(t *testing.T) {
+		// {"a": 32([1]), "b": 2}
+		in := []byte{0xa2, 0x61, 'a', 0xd8, 0x20, 0x81, 0x01, 0x61, 'b', 0x02}
+
+		var rec tagRecording
+		if err := Parse(in, &rec); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []uint64{32}, rec.tags)
+		rec.Recording.Assert(t, sftest.Recording(sftest.Obj(2, structform.AnyType,
+			"a", sftest.Arr(1, structform.AnyType, sftest.Uint8Rec{1}),
+			"b", sftest.Uint8Rec{2},
+		)))
+	})
+
+	t.Run("time and bignum", func(t *testing.T) {
+		// {"a": 1(1), "b": 2(h'01'), "c": 2}
+		in := []byte{
+			0xa3,
+			0x61, 'a', 0xc1, 0x01,
+			0x61, 'b', 0xc2, 0x41, 0x01,
+			0x61, 'c', 0x02,
+		}
+
+		var rec extRecording
+		if err := Parse(in, &rec); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []time.Time{time.Unix(1, 0).UTC()}, rec.times)
+		assert.Equal(t, []*big.Int{big.NewInt(1)}, rec.bigs)
+		assert.Equal(t, sftest.Recording{
+			sftest.ObjectStartRec{3, structform.AnyType},
+			sftest.ObjectKeyRec{"a"},
+			sftest.ObjectKeyRec{"b"},
+			sftest.ObjectKeyRec{"c"},
+			sftest.Uint8Rec{2},
+			sftest.ObjectFinishRec{},
+		}, rec.Recording)
+	})
+}
+
+func TestTimeBignumUndefinedRoundtrip(t *testing.T) {
+	ts := time.Date(2020, 2, 3, 4, 5, 6, 7, time.UTC)
+	big1, _ := new(big.Int).SetString("18446744073709551616", 10)
+	big2, _ := new(big.Int).SetString("-18446744073709551617", 10)
+
+	buf := bytes.NewBuffer(nil)
+	vs := NewVisitor(buf)
+	assert.NoError(t, vs.OnArrayStart(4, structform.AnyType))
+	assert.NoError(t, vs.OnTime(ts))
+	assert.NoError(t, vs.OnBigInt(big1))
+	assert.NoError(t, vs.OnBigInt(big2))
+	assert.NoError(t, vs.OnUndefined())
+	assert.NoError(t, vs.OnArrayFinished())
+
+	var rec extRecording
+	if err := Parse(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []time.Time{ts}, rec.times)
+	assert.Equal(t, []*big.Int{big1, big2}, rec.bigs)
+	assert.Equal(t, 1, rec.undefs)
+	assert.Equal(t, 0, len(rec.tags))
+}
+
+func TestShortestFloat(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected []byte
+	}{
+		{0, []byte{0xf9, 0x00, 0x00}},
+		{1.5, []byte{0xf9, 0x3e, 0x00}},
+		{65504, []byte{0xf9, 0x7b, 0xff}},
+		{100000, []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}},
+		{1.1, []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}},
+		{math.NaN(), []byte{0xf9, 0x7e, 0x00}},
+		{math.Inf(-1), []byte{0xf9, 0xfc, 0x00}},
+	}
+
+	for _, test := range tests {
+		buf := bytes.NewBuffer(nil)
+		vs := NewVisitor(buf)
+		vs.SetShortestFloat(true)
+		if assert.NoError(t, vs.OnFloat64(test.value)) {
+			assert.Equal(t, test.expected, buf.Bytes())
+		}
+	}
+}
+
+func TestShortestFloatSettings(t *testing.T) {
+	encode := func(vs *Visitor, buf *bytes.Buffer) []byte {
+		buf.Reset()
+		if err := vs.OnFloat64(1.5); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	half := []byte{0xf9, 0x3e, 0x00}
+	double := []byte{0xfb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}
+
+	buf := bytes.NewBuffer(nil)
+	vs := NewVisitor(buf)
+	vs.SetShortestFloat(true)
+	vs.SetCanonical(false)
+	assert.Equal(t, half, encode(vs, buf))
+
+	vs.SetShortestFloat(false)
+	vs.SetCanonical(true)
+	assert.Equal(t, half, encode(vs, buf))
+
+	vs.SetCanonical(false)
+	assert.Equal(t, double, encode(vs, buf))
+}
+
+func TestCanonicalEncoding(t *testing.T) {
+	tests := []struct {
+		title    string
+		rec      sftest.Recording
+		expected []byte
+	}{
+		{
+			"indefinite array",
+			sftest.Recording(sftest.Arr(-1, structform.AnyType,
+				sftest.Uint64Rec{1},
+				sftest.Arr(-1, structform.AnyType, sftest.Float64Rec{1.5}),
+				sftest.Int8ArrRec{[]int8{-1}},
+			)),
+			[]byte{0x83, 0x01, 0x81, 0xf9, 0x3e, 0x00, 0x81, 0x20},
+		},
+		{
+			"sorted keys",
+			sftest.Recording(sftest.Obj(-1, structform.AnyType,
+				"bb", sftest.Obj(2, structform.AnyType,
+					"b", sftest.NilRec{},
+					"a", sftest.BoolRec{true},
+				),
+				"c", sftest.Uint8Rec{1},
+				"aaa", sftest.Uint8Rec{2},
+			)),
+			[]byte{
+				0xa3,
+				0x61, 'c', 0x01,
+				0x62, 'b', 'b', 0xa2, 0x61, 'a', 0xf5, 0x61, 'b', 0xf6,
+				0x63, 'a', 'a', 'a', 0x02,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			vs := NewVisitor(buf)
+			vs.SetCanonical(true)
+			if err := test.rec.Replay(vs); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expected, buf.Bytes())
+		})
+	}
+}
+
+func TestCanonicalMapOrder(t *testing.T) {
+	m := map[string]uint{}
+	for i := 0; i < 100; i++ {
+		m[string(rune('A'+i%26))+string(rune('a'+i/26))] = uint(i)
+	}
+
+	var expected []byte
+	for i := 0; i < 10; i++ {
+		buf := bytes.NewBuffer(nil)
+		vs := NewVisitor(buf)
+		vs.SetCanonical(true)
+		rec := sftest.Recording{sftest.UintObjRec{m}}
+		if err := rec.Replay(vs); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected == nil {
+			expected = buf.Bytes()
+		} else {
+			assert.Equal(t, expected, buf.Bytes())
+		}
+	}
+}
+
+func TestParseErrorOffset(t *testing.T) {
+	// array [1, 2, <reserved simple value>]
+	in := []byte{0x83, 0x01, 0x02, 0xfc}
+
+	var rec sftest.Recording
+	err := Parse(in, &rec)
+	serr, ok := err.(*structform.SyntaxError)
+	if !ok {
+		t.Fatalf("expected syntax error, got: %v", err)
+	}
+	assert.Equal(t, int64(3), serr.Offset)
+	assert.Equal(t, 0, serr.Line)
+}
+
+func TestParseVisitorError(t *testing.T) {
+	// array [1]
+	in := []byte{0x81, 0x01}
+
+	var rec sftest.Recording
+	errVisitor := errors.New("visitor failed")
+	err := Parse(in, &failingVisitor{Visitor: &rec, err: errVisitor})
+	assert.Equal(t, errVisitor, err)
+}
+
+// failingVisitor fails on the first unsigned integer.
+type failingVisitor struct {
+	structform.Visitor
+	err error
+}
+
+func (v *failingVisitor) OnUint8(uint8) error { return v.err }
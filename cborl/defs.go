@@ -54,6 +54,14 @@ const (
 	codeBreak       uint8 = lenIndef | majorOther
 )
 
+// tags with special handling
+const (
+	tagDateTime  uint64 = 0
+	tagEpoch     uint64 = 1
+	tagPosBignum uint64 = 2
+	tagNegBignum uint64 = 3
+)
+
 func str2Bytes(s string) []byte {
 	return unsafe.Str2Bytes(s)
 }
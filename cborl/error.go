@@ -24,7 +24,23 @@ func errTODO() error {
 	panic(err)
 }
 
-var errInvalidCode = errors.New("invalid type code")
-var errTextKeyRequired = errors.New("only text keys supported")
-var errIndefByteSeq = errors.New("text/bytes of indefinite length not supported")
-var errEmptyKey = errors.New("object keys must not be empty")
+var errInvalidCode = newParseError("invalid type code")
+var errTextKeyRequired = newParseError("only text keys supported")
+var errIndefByteSeq = newParseError("text/bytes of indefinite length not supported")
+var errEmptyKey = newParseError("object keys must not be empty")
+var errInvalidTagContent = newParseError("invalid content for tag")
+var errNestedTag = newParseError("nested tags not supported in date/time or bignum")
+
+// parseError marks errors detected by the parser. Only parse errors are
+// reported as structform.SyntaxError, errors returned by the visitor are
+// passed through unchanged.
+type parseError struct {
+	err error
+}
+
+func newParseError(msg string) error {
+	return &parseError{errors.New(msg)}
+}
+
+func (e *parseError) Error() string { return e.err.Error() }
+func (e *parseError) Unwrap() error { return e.err }
@@ -29,6 +29,13 @@ type Parser struct {
 	visitor    structform.Visitor
 	strVisitor structform.StringRefVisitor
 
+	// optional visitor extensions
+	tagVisitor   TagVisitor
+	timeVisitor  TimeVisitor
+	bigVisitor   BigIntVisitor
+	undefVisitor UndefinedVisitor
+	tagged       taggedValue
+
 	// last fail state
 	err error
 
@@ -39,6 +46,9 @@ type Parser struct {
 
 	buffer  []byte
 	buffer0 [64]byte
+
+	// input offset of the next byte to be processed
+	offset int64
 }
 
 type state struct {
@@ -91,6 +101,10 @@ func (p *Parser) init(vs structform.Visitor) {
 		visitor:    vs,
 		strVisitor: structform.MakeStringRefVisitor(vs),
 	}
+	p.tagVisitor, _ = vs.(TagVisitor)
+	p.timeVisitor, _ = vs.(TimeVisitor)
+	p.bigVisitor, _ = vs.(BigIntVisitor)
+	p.undefVisitor, _ = vs.(UndefinedVisitor)
 	p.buffer = p.buffer0[:0]
 	p.length.init()
 	p.state.init(state{stValue, stStart})
@@ -128,11 +142,13 @@ func (p *Parser) feed(b []byte) error {
 func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 	var (
 		orig = b
+		last []byte
 		done bool
 		err  error
 	)
 
 	for {
+		last = b
 		b, done, err = p.execStep(b)
 		if done || err != nil {
 			break
@@ -147,7 +163,27 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 			break
 		}
 	}
-	return len(orig) - len(b), done, err
+
+	if err != nil {
+		if b == nil {
+			b = last
+		}
+		consumed := len(orig) - len(b)
+		return consumed, false, p.syntaxError(err, consumed)
+	}
+
+	p.offset += int64(len(orig) - len(b))
+	return len(orig) - len(b), done, nil
+}
+
+// syntaxError annotates parse errors with the input offset after processing
+// another n bytes. Errors returned by the visitor are passed through
+// unchanged.
+func (p *Parser) syntaxError(err error, n int) error {
+	if _, ok := err.(*parseError); !ok {
+		return err // already annotated or returned by the visitor
+	}
+	return &structform.SyntaxError{Err: err, Offset: p.offset + int64(n)}
 }
 
 func (p *Parser) execStep(b []byte) ([]byte, bool, error) {
@@ -168,6 +204,10 @@ func (p *Parser) execStep(b []byte) ([]byte, bool, error) {
 		b, done, err = p.stepUint(b)
 	case majorNeg:
 		b, done, err = p.stepNeg(b)
+	case majorTag:
+		b, err = p.stepTag(b)
+	case codeHalfFloat:
+		b, done, err = p.stepHalfFloat(b)
 	case codeSingleFloat:
 		b, done, err = p.stepSingleFloat(b)
 	case codeDoubleFloat:
@@ -278,7 +318,8 @@ func (p *Parser) execStep(b []byte) ([]byte, bool, error) {
 	case stKey:
 		b, done, err = p.stepKey(b)
 	case stElem:
-		p.state.pop()
+		// keep stElem on the stack until the value is complete, such that tags
+		// and nested items are not mistaken for the next key
 		b, done, err = p.stepValue(b)
 
 	default:
@@ -295,6 +336,10 @@ func (p *Parser) popState() (bool, error) {
 
 func (p *Parser) onValue() (bool, error) {
 	switch p.state.current.major {
+	case stElem:
+		p.state.pop()
+		return p.onValue()
+
 	case majorArr:
 		p.length.current--
 		_, done, err := p.arrayHandleLen()
@@ -358,7 +403,16 @@ func (p *Parser) stepValue(b []byte) ([]byte, bool, error) {
 		return p.initSub(major, minor, b[1:])
 
 	case majorTag:
-		return nil, false, errTODO()
+		minor := b[0] & minorMask
+		if minor < len8b {
+			return b[1:], false, p.onTag(uint64(minor))
+		}
+		if minor > len64b {
+			return nil, false, errInvalidCode
+		}
+
+		p.state.push(state{major, minor})
+		return b[1:], false, nil
 
 	default:
 		var (
@@ -379,15 +433,23 @@ func (p *Parser) stepValue(b []byte) ([]byte, bool, error) {
 				done, err = p.onValue()
 			}
 			return b[1:], done, err
-		case codeNull, codeUndef:
+		case codeUndef:
+			if p.undefVisitor != nil && !p.tagged.active {
+				err = p.undefVisitor.OnUndefined()
+			} else {
+				err = p.visitor.OnNil()
+			}
+			if err == nil {
+				done, err = p.onValue()
+			}
+			return b[1:], done, err
+		case codeNull:
 			err = p.visitor.OnNil()
 			if err == nil {
 				done, err = p.onValue()
 			}
 			return b[1:], done, err
-		case codeHalfFloat:
-			return b[1:], false, errTODO()
-		case codeSingleFloat, codeDoubleFloat:
+		case codeHalfFloat, codeSingleFloat, codeDoubleFloat:
 			p.state.push(state{b[0], stStart})
 			return b[1:], false, nil
 		}
@@ -644,6 +706,46 @@ func (p *Parser) stepNeg(in []byte) (b []byte, done bool, err error) {
 	return
 }
 
+func (p *Parser) stepTag(in []byte) (b []byte, err error) {
+	var (
+		done bool
+		tag  uint64
+	)
+
+	b = in
+	switch p.state.current.minor {
+	case len8b:
+		b, done, tag = b[1:], true, uint64(b[0])
+	case len16b:
+		var v uint16
+		b, done, v = p.getUint16(b)
+		tag = uint64(v)
+	case len32b:
+		var v uint32
+		b, done, v = p.getUint32(b)
+		tag = uint64(v)
+	case len64b:
+		b, done, tag = p.getUint64(b)
+	}
+
+	if done {
+		p.state.pop()
+		err = p.onTag(tag)
+	}
+	return
+}
+
+func (p *Parser) stepHalfFloat(in []byte) (b []byte, done bool, err error) {
+	var tmp uint16
+	if b, done, tmp = p.getUint16(in); done {
+		err = p.visitor.OnFloat32(halfToFloat32(tmp))
+		if err == nil {
+			done, err = p.popState()
+		}
+	}
+	return
+}
+
 func (p *Parser) stepSingleFloat(in []byte) (b []byte, done bool, err error) {
 	var tmp uint32
 	if b, done, tmp = p.getUint32(in); done {
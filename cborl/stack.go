@@ -29,6 +29,22 @@ type lengthStack struct {
 	current int64
 }
 
+// frameStack tracks the arrays and objects currently open in the Visitor.
+type frameStack struct {
+	stack   []frame
+	stack0  [32]frame
+	current frame
+}
+
+type frame struct {
+	major    uint8 // majorArr or majorMap
+	len      int   // declared length. Negative if length is unknown
+	count    int   // number of elements or keys written so far
+	buffered bool  // contents are buffered in canonical mode
+	offset   int   // start of contents in write buffer
+	entry    int   // index of first map entry in canonical mode
+}
+
 func (s *stateStack) init(s0 state) {
 	s.current = s0
 	s.stack = s.stack0[:0]
@@ -72,3 +88,26 @@ func (s *lengthStack) pop() int64 {
 		return old
 	}
 }
+
+func (s *frameStack) init() {
+	s.stack = s.stack0[:0]
+}
+
+func (s *frameStack) push(f frame) {
+	s.stack = append(s.stack, s.current)
+	s.current = f
+}
+
+func (s *frameStack) pop() frame {
+	if len(s.stack) == 0 {
+		old := s.current
+		s.current = frame{}
+		return old
+	}
+
+	last := len(s.stack) - 1
+	old := s.current
+	s.current = s.stack[last]
+	s.stack = s.stack[:last]
+	return old
+}
@@ -0,0 +1,195 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cborl
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// TagVisitor is an optional extension to structform.Visitor. If the visitor
+// passed to the Parser implements TagVisitor, tags are reported via OnTag
+// right before the tagged item. Tags are dropped otherwise.
+type TagVisitor interface {
+	OnTag(tag uint64) error
+}
+
+// TimeVisitor is an optional extension to structform.Visitor. If implemented,
+// items tagged as standard date/time string (tag 0) or epoch based date/time
+// (tag 1) are reported via OnTime instead of OnTag and the raw item.
+type TimeVisitor interface {
+	OnTime(t time.Time) error
+}
+
+// BigIntVisitor is an optional extension to structform.Visitor. If
+// implemented, bignums (tags 2 and 3) are reported via OnBigInt instead of
+// OnTag and the raw byte string.
+type BigIntVisitor interface {
+	OnBigInt(i *big.Int) error
+}
+
+// UndefinedVisitor is an optional extension to structform.Visitor. If
+// implemented, the simple value undefined is reported via OnUndefined instead
+// of OnNil.
+type UndefinedVisitor interface {
+	OnUndefined() error
+}
+
+// taggedValue intercepts the item following a date/time or bignum tag,
+// reporting the converted value to the original visitor.
+type taggedValue struct {
+	p   *Parser
+	tag uint64
+
+	active  bool
+	inBytes bool
+	buf     []byte
+
+	// original visitors to restore after the tagged item has been processed
+	visitor    structform.Visitor
+	strVisitor structform.StringRefVisitor
+}
+
+func (p *Parser) onTag(tag uint64) error {
+	if p.tagged.active {
+		return errNestedTag
+	}
+
+	switch {
+	case (tag == tagDateTime || tag == tagEpoch) && p.timeVisitor != nil,
+		(tag == tagPosBignum || tag == tagNegBignum) && p.bigVisitor != nil:
+		p.tagged.begin(p, tag)
+		return nil
+	case p.tagVisitor != nil:
+		return p.tagVisitor.OnTag(tag)
+	}
+	return nil
+}
+
+func (t *taggedValue) begin(p *Parser, tag uint64) {
+	*t = taggedValue{
+		p:          p,
+		tag:        tag,
+		active:     true,
+		buf:        t.buf[:0],
+		visitor:    p.visitor,
+		strVisitor: p.strVisitor,
+	}
+	p.visitor, p.strVisitor = t, t
+}
+
+func (t *taggedValue) end() {
+	t.active = false
+	t.p.visitor, t.p.strVisitor = t.visitor, t.strVisitor
+}
+
+func (t *taggedValue) onTime(ts time.Time) error {
+	t.end()
+	return t.p.timeVisitor.OnTime(ts)
+}
+
+func (t *taggedValue) onEpoch(sec int64, nsec int64) error {
+	if t.tag != tagEpoch {
+		return errInvalidTagContent
+	}
+	return t.onTime(time.Unix(sec, nsec).UTC())
+}
+
+func (t *taggedValue) onUint(u uint64) error {
+	if t.inBytes {
+		t.buf = append(t.buf, uint8(u))
+		return nil
+	}
+	if u > math.MaxInt64 {
+		return errInvalidTagContent
+	}
+	return t.onEpoch(int64(u), 0)
+}
+
+func (t *taggedValue) onFloat(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return errInvalidTagContent
+	}
+	sec, frac := math.Modf(f)
+	return t.onEpoch(int64(sec), int64(frac*1e9))
+}
+
+func (t *taggedValue) OnString(s string) error {
+	if t.tag != tagDateTime {
+		return errInvalidTagContent
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return err
+	}
+	return t.onTime(ts)
+}
+
+func (t *taggedValue) OnStringRef(s []byte) error {
+	return t.OnString(bytes2Str(s))
+}
+
+func (t *taggedValue) OnArrayStart(l int, baseType structform.BaseType) error {
+	if baseType != structform.ByteType || t.inBytes ||
+		(t.tag != tagPosBignum && t.tag != tagNegBignum) {
+		return errInvalidTagContent
+	}
+	t.inBytes = true
+	return nil
+}
+
+func (t *taggedValue) OnArrayFinished() error {
+	if !t.inBytes {
+		return errInvalidTagContent
+	}
+
+	i := new(big.Int).SetBytes(t.buf)
+	if t.tag == tagNegBignum {
+		i.Not(i)
+	}
+
+	t.end()
+	return t.p.bigVisitor.OnBigInt(i)
+}
+
+func (t *taggedValue) OnByte(b byte) error     { return t.onUint(uint64(b)) }
+func (t *taggedValue) OnUint8(u uint8) error   { return t.onUint(uint64(u)) }
+func (t *taggedValue) OnUint16(u uint16) error { return t.onUint(uint64(u)) }
+func (t *taggedValue) OnUint32(u uint32) error { return t.onUint(uint64(u)) }
+func (t *taggedValue) OnUint64(u uint64) error { return t.onUint(u) }
+func (t *taggedValue) OnUint(u uint) error     { return t.onUint(uint64(u)) }
+
+func (t *taggedValue) OnInt8(i int8) error   { return t.onEpoch(int64(i), 0) }
+func (t *taggedValue) OnInt16(i int16) error { return t.onEpoch(int64(i), 0) }
+func (t *taggedValue) OnInt32(i int32) error { return t.onEpoch(int64(i), 0) }
+func (t *taggedValue) OnInt64(i int64) error { return t.onEpoch(i, 0) }
+func (t *taggedValue) OnInt(i int) error     { return t.onEpoch(int64(i), 0) }
+
+func (t *taggedValue) OnFloat32(f float32) error { return t.onFloat(float64(f)) }
+func (t *taggedValue) OnFloat64(f float64) error { return t.onFloat(f) }
+
+func (t *taggedValue) OnNil() error                                 { return errInvalidTagContent }
+func (t *taggedValue) OnBool(bool) error                            { return errInvalidTagContent }
+func (t *taggedValue) OnObjectStart(int, structform.BaseType) error { return errInvalidTagContent }
+func (t *taggedValue) OnObjectFinished() error                      { return errInvalidTagContent }
+func (t *taggedValue) OnKey(string) error                           { return errInvalidTagContent }
+func (t *taggedValue) OnKeyRef([]byte) error                        { return errInvalidTagContent }
@@ -18,9 +18,13 @@
 package cborl
 
 import (
+	"bytes"
 	"encoding/binary"
 	"io"
 	"math"
+	"math/big"
+	"sort"
+	"time"
 
 	structform "github.com/elastic/go-structform"
 )
@@ -29,74 +33,221 @@ type Visitor struct {
 	w       writer
 	scratch [16]byte
 
-	length lengthStack
+	frames frameStack
+
+	shortestFloat bool
+
+	// canonical encoding state
+	canonical bool
+	entries   []mapEntry
+	tmp       []byte
 }
 
 type writer struct {
 	out io.Writer
+
+	// contents of open arrays/objects that require buffering in canonical mode
+	buf      []byte
+	buffered int
+}
+
+// mapEntry holds the offsets of a buffered key-value pair
+type mapEntry struct {
+	key, value, end int
 }
 
-func (w writer) write(b []byte) error {
+func (w *writer) write(b []byte) error {
+	if w.buffered > 0 {
+		w.buf = append(w.buf, b...)
+		return nil
+	}
+
 	_, err := w.out.Write(b)
 	return err
 }
 
+func (w *writer) flush() error {
+	_, err := w.out.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
 func NewVisitor(out io.Writer) *Visitor {
-	v := &Visitor{w: writer{out}}
-	v.length.stack = v.length.stack0[:0]
+	v := &Visitor{w: writer{out: out}}
+	v.frames.init()
 	return v
 }
 
+// SetCanonical configures the visitor to produce the core deterministic
+// encoding as defined in RFC 8949: integers, lengths and floats use the
+// shortest encoding, arrays and maps always have a definite length, and map
+// keys are sorted by their encoded bytes.
+// Arrays and maps of unknown length and all maps are buffered until finished.
+// The setting must not be changed while encoding a value.
+// Floats use the shortest encoding while canonical mode is enabled,
+// independent of SetShortestFloat.
+func (vs *Visitor) SetCanonical(b bool) {
+	vs.canonical = b
+}
+
+// SetShortestFloat configures the visitor to encode floats using the
+// shortest of the half, single or double precision encodings that preserves
+// the value.
+func (vs *Visitor) SetShortestFloat(b bool) {
+	vs.shortestFloat = b
+}
+
+func (vs *Visitor) useShortestFloat() bool {
+	return vs.shortestFloat || vs.canonical
+}
+
 func (vs *Visitor) writeByte(b byte) error {
 	vs.scratch[0] = b
 	return vs.w.write(vs.scratch[:1])
 }
 
 func (vs *Visitor) OnObjectStart(len int, baseType structform.BaseType) error {
-	if err := vs.optLen(majorMap, len); err != nil {
-		return err
-	}
-
-	vs.length.push(int64(len))
-	return nil
+	return vs.structStart(majorMap, len)
 }
 
 func (vs *Visitor) OnObjectFinished() error {
-	if vs.length.pop() < 0 {
-		return vs.writeByte(codeBreak)
-	}
-	return nil
+	return vs.structEnd()
 }
 
 func (vs *Visitor) OnKey(s string) error {
-	return vs.string(str2Bytes(s))
+	return vs.key(str2Bytes(s))
 }
 
 func (vs *Visitor) OnKeyRef(s []byte) error {
-	return vs.string(s)
+	return vs.key(s)
 }
 
 func (vs *Visitor) OnArrayStart(len int, baseType structform.BaseType) error {
-	if err := vs.optLen(majorArr, len); err != nil {
-		return err
+	return vs.structStart(majorArr, len)
+}
+
+func (vs *Visitor) OnArrayFinished() error {
+	return vs.structEnd()
+}
+
+// onValue counts the elements of the current array.
+func (vs *Visitor) onValue() {
+	if vs.frames.current.major == majorArr {
+		vs.frames.current.count++
 	}
+}
+
+func (vs *Visitor) structStart(major uint8, l int) error {
+	vs.onValue()
 
-	vs.length.push(int64(len))
+	if !vs.canonical {
+		vs.frames.push(frame{major: major, len: l})
+		return vs.optLen(major, l)
+	}
+
+	if l >= 0 {
+		if err := vs.uint64(major, uint64(l)); err != nil {
+			return err
+		}
+	}
+
+	f := frame{major: major, len: l, entry: len(vs.entries)}
+	if l < 0 || major == majorMap {
+		f.buffered = true
+		vs.w.buffered++
+	}
+	f.offset = len(vs.w.buf)
+	vs.frames.push(f)
 	return nil
 }
 
-func (vs *Visitor) OnArrayFinished() error {
-	if vs.length.pop() < 0 {
-		return vs.writeByte(codeBreak)
+func (vs *Visitor) structEnd() error {
+	f := vs.frames.pop()
+	if !vs.canonical {
+		if f.len < 0 {
+			return vs.writeByte(codeBreak)
+		}
+		return nil
+	}
+
+	if f.major == majorMap {
+		vs.sortEntries(&f)
+	}
+	if f.len < 0 {
+		vs.insertHead(f.offset, f.major, uint64(f.count))
+	}
+
+	if f.buffered {
+		vs.w.buffered--
+		if vs.w.buffered == 0 {
+			return vs.w.flush()
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) key(s []byte) error {
+	if !vs.canonical {
+		return vs.string(s)
+	}
+
+	vs.frames.current.count++
+	start := len(vs.w.buf)
+	if err := vs.string(s); err != nil {
+		return err
 	}
+	vs.entries = append(vs.entries, mapEntry{key: start, value: len(vs.w.buf)})
 	return nil
 }
 
+// sortEntries reorders the buffered key-value pairs of a map by the encoded
+// bytes of the keys.
+func (vs *Visitor) sortEntries(f *frame) {
+	entries := vs.entries[f.entry:]
+	defer func() { vs.entries = vs.entries[:f.entry] }()
+	if len(entries) < 2 {
+		return
+	}
+
+	buf := vs.w.buf
+	for i := range entries {
+		if i+1 < len(entries) {
+			entries[i].end = entries[i+1].key
+		} else {
+			entries[i].end = len(buf)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		return bytes.Compare(buf[a.key:a.value], buf[b.key:b.value]) < 0
+	})
+
+	vs.tmp = append(vs.tmp[:0], buf[f.offset:]...)
+	pos := f.offset
+	for _, e := range entries {
+		pos += copy(buf[pos:], vs.tmp[e.key-f.offset:e.end-f.offset])
+	}
+}
+
+// insertHead inserts the type header at offset into the write buffer.
+func (vs *Visitor) insertHead(offset int, major uint8, v uint64) {
+	h := vs.head(major, v)
+
+	w := &vs.w
+	end := len(w.buf)
+	w.buf = append(w.buf, h...)
+	copy(w.buf[offset+len(h):], w.buf[offset:end])
+	copy(w.buf[offset:], h)
+}
+
 func (vs *Visitor) OnNil() error {
+	vs.onValue()
 	return vs.writeByte(codeNull)
 }
 
 func (vs *Visitor) OnBool(b bool) error {
+	vs.onValue()
 	if b {
 		return vs.writeByte(codeTrue)
 	}
@@ -104,74 +255,152 @@ func (vs *Visitor) OnBool(b bool) error {
 }
 
 func (vs *Visitor) OnString(s string) error {
+	vs.onValue()
 	return vs.string(str2Bytes(s))
 }
 
 func (vs *Visitor) OnStringRef(s []byte) error {
+	vs.onValue()
 	return vs.string(s)
 }
 
 func (vs *Visitor) OnInt8(i int8) error {
+	vs.onValue()
 	return vs.int8(i)
 }
 
 func (vs *Visitor) OnInt16(i int16) error {
+	vs.onValue()
 	return vs.int16(i)
 }
 
 func (vs *Visitor) OnInt32(i int32) error {
+	vs.onValue()
 	return vs.int32(i)
 }
 
 func (vs *Visitor) OnInt64(i int64) error {
+	vs.onValue()
 	return vs.int64(i)
 }
 
 func (vs *Visitor) OnInt(i int) error {
+	vs.onValue()
 	return vs.int64(int64(i))
 }
 
 func (vs *Visitor) OnByte(b byte) error {
+	vs.onValue()
 	return vs.uint8(majorUint, b)
 }
 
 func (vs *Visitor) OnUint8(u uint8) error {
+	vs.onValue()
 	return vs.uint8(majorUint, u)
 }
 
 func (vs *Visitor) OnUint16(u uint16) error {
+	vs.onValue()
 	return vs.uint16(majorUint, u)
 }
 
 func (vs *Visitor) OnUint32(u uint32) error {
+	vs.onValue()
 	return vs.uint32(majorUint, u)
 }
 
 func (vs *Visitor) OnUint64(u uint64) error {
+	vs.onValue()
 	return vs.uint64(majorUint, u)
 }
 
 func (vs *Visitor) OnUint(u uint) error {
+	vs.onValue()
 	return vs.uint64(majorUint, uint64(u))
 }
 
 func (vs *Visitor) OnFloat32(f float32) error {
+	vs.onValue()
+	return vs.float32(f)
+}
+
+func (vs *Visitor) OnFloat64(f float64) error {
+	vs.onValue()
+	return vs.float64(f)
+}
+
+func (vs *Visitor) float32(f float32) error {
+	if vs.useShortestFloat() {
+		if h, ok := float32ToHalf(f); ok {
+			return vs.half(h)
+		}
+	}
+
 	b := math.Float32bits(f)
 	vs.scratch[0] = codeSingleFloat
 	binary.BigEndian.PutUint32(vs.scratch[1:5], b)
 	return vs.w.write(vs.scratch[:5])
 }
 
-func (vs *Visitor) OnFloat64(f float64) error {
+func (vs *Visitor) float64(f float64) error {
+	if vs.useShortestFloat() {
+		if f32 := float32(f); float64(f32) == f || math.IsNaN(f) {
+			return vs.float32(f32)
+		}
+	}
+
 	b := math.Float64bits(f)
 	vs.scratch[0] = codeDoubleFloat
 	binary.BigEndian.PutUint64(vs.scratch[1:9], b)
 	return vs.w.write(vs.scratch[:9])
 }
 
+// OnTag writes a tag. The next value written becomes the tagged item.
+func (vs *Visitor) OnTag(tag uint64) error {
+	return vs.uint64(majorTag, tag)
+}
+
+// OnTime writes t as standard date/time string (tag 0).
+func (vs *Visitor) OnTime(t time.Time) error {
+	vs.onValue()
+	if err := vs.OnTag(tagDateTime); err != nil {
+		return err
+	}
+	return vs.string(str2Bytes(t.Format(time.RFC3339Nano)))
+}
+
+// OnBigInt writes i as integer if possible, or as bignum (tags 2 and 3) if i
+// exceeds the 64bit range supported by CBOR integers.
+func (vs *Visitor) OnBigInt(i *big.Int) error {
+	vs.onValue()
+	if i.Sign() >= 0 {
+		if i.IsUint64() {
+			return vs.uint64(majorUint, i.Uint64())
+		}
+		if err := vs.OnTag(tagPosBignum); err != nil {
+			return err
+		}
+		return vs.bytes(majorBytes, i.Bytes())
+	}
+
+	n := new(big.Int).Not(i) // -1 - i
+	if n.IsUint64() {
+		return vs.uint64(majorNeg, n.Uint64())
+	}
+	if err := vs.OnTag(tagNegBignum); err != nil {
+		return err
+	}
+	return vs.bytes(majorBytes, n.Bytes())
+}
+
+func (vs *Visitor) OnUndefined() error {
+	vs.onValue()
+	return vs.writeByte(codeUndef)
+}
+
 func (vs *Visitor) OnBoolArray(a []bool) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnBool(v); err != nil {
@@ -179,159 +408,167 @@ func (vs *Visitor) OnBoolArray(a []bool) error {
 		}
 	}
 
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnStringArray(a []string) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnString(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnInt8Array(a []int8) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnInt8(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnInt16Array(a []int16) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnInt16(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnInt32Array(a []int32) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnInt32(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnInt64Array(a []int64) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnInt64(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnIntArray(a []int) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnInt(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnBytes(a []byte) error {
+	vs.onValue()
 	return vs.bytes(majorBytes, a)
 }
 
 func (vs *Visitor) OnUint8Array(a []uint8) error {
+	vs.onValue()
 	return vs.bytes(majorBytes, a)
 }
 
 func (vs *Visitor) OnUint16Array(a []uint16) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnUint16(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnUint32Array(a []uint32) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnUint32(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnUint64Array(a []uint64) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnUint64(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnUintArray(a []uint) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnUint(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnFloat32Array(a []float32) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnFloat32(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
 }
 
 func (vs *Visitor) OnFloat64Array(a []float64) error {
-	if err := vs.arrLen(len(a)); err != nil {
-		return nil
+	if err := vs.OnArrayStart(len(a), structform.AnyType); err != nil {
+		return err
 	}
 	for _, v := range a {
 		if err := vs.OnFloat64(v); err != nil {
 			return err
 		}
 	}
-	return nil
+	return vs.OnArrayFinished()
+}
+
+func (vs *Visitor) half(h uint16) error {
+	vs.scratch[0] = codeHalfFloat
+	binary.BigEndian.PutUint16(vs.scratch[1:3], h)
+	return vs.w.write(vs.scratch[:3])
 }
 
 func (vs *Visitor) string(s []byte) error {
@@ -426,23 +663,30 @@ func (vs *Visitor) uint32(major uint8, v uint32) error {
 }
 
 func (vs *Visitor) uint64(major uint8, v uint64) error {
+	return vs.w.write(vs.head(major, v))
+}
+
+// head encodes the type header with the shortest length encoding into the
+// scratch buffer.
+func (vs *Visitor) head(major uint8, v uint64) []byte {
 	switch {
 	case v < uint64(len8b):
-		return vs.writeByte(major | uint8(v))
+		vs.scratch[0] = major | uint8(v)
+		return vs.scratch[:1]
 	case v <= math.MaxUint8:
 		vs.scratch[0], vs.scratch[1] = major|len8b, uint8(v)
-		return vs.w.write(vs.scratch[:2])
+		return vs.scratch[:2]
 	case v <= math.MaxUint16:
 		vs.scratch[0] = major | len16b
 		binary.BigEndian.PutUint16(vs.scratch[1:3], uint16(v))
-		return vs.w.write(vs.scratch[:3])
+		return vs.scratch[:3]
 	case v <= math.MaxUint32:
 		vs.scratch[0] = major | len32b
 		binary.BigEndian.PutUint32(vs.scratch[1:5], uint32(v))
-		return vs.w.write(vs.scratch[:5])
+		return vs.scratch[:5]
 	default:
 		vs.scratch[0] = major | len64b
 		binary.BigEndian.PutUint64(vs.scratch[1:9], uint64(v))
-		return vs.w.write(vs.scratch[:9])
+		return vs.scratch[:9]
 	}
 }
@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package structform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError is returned by parsers if processing the input fails. It
+// records the location in the input stream the error was detected at. Err
+// holds the error detected by the parser. Errors returned by the visitor are
+// passed to the caller unchanged.
+type SyntaxError struct {
+	Err error
+
+	// Offset is the number of bytes successfully processed before the error
+	// was detected.
+	Offset int64
+
+	// Line and Column report the 1-based text position of the error. Both are
+	// 0 for binary formats.
+	Line, Column int
+
+	// Path is the location of the failing value in the document. Object keys
+	// are separated by '.', array indices are given in brackets
+	// (e.g. `outputs[2].hosts`). Path is empty at the top-level or if the
+	// parser does not track paths.
+	Path string
+}
+
+func (e *SyntaxError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("parse error")
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, " at line %v, column %v (offset %v)", e.Line, e.Column, e.Offset)
+	} else {
+		fmt.Fprintf(&sb, " at offset %v", e.Offset)
+	}
+	if e.Path != "" {
+		fmt.Fprintf(&sb, " in '%v'", e.Path)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
@@ -1,3 +1,19 @@
 module github.com/elastic/go-structform
 
-go 1.12
+go 1.21
+
+require (
+	github.com/json-iterator/go v1.1.12
+	github.com/stretchr/testify v1.3.0
+	github.com/ugorji/go/codec v1.3.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	github.com/google/gofuzz v1.0.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.1.0 // indirect
+)
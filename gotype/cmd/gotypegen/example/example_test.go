@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package example
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/gotype"
+	"github.com/elastic/go-structform/json"
+)
+
+// reflEvent has the fields of Event, but no generated methods.
+type reflEvent Event
+
+var testEvents = map[string]Event{
+	"empty": {},
+	"all fields": {
+		Timestamp: time.Date(2020, 2, 3, 4, 5, 6, 0, time.UTC),
+		Message:   "hello",
+		Level:     LevelError,
+		Count:     -3,
+		Sequence:  1 << 40,
+		Ratio:     0.5,
+		Success:   true,
+		Tags:      []string{"a", "b"},
+		Labels:    map[string]string{"env": "test"},
+		Host:      &Host{Name: "h1", IP: []string{"127.0.0.1"}},
+		Source:    Host{Name: "h2"},
+		Duration:  new(int64),
+		Fields:    map[string]interface{}{"nested": []interface{}{"x"}},
+		Trace:     Trace{TraceID: "abc", SpanID: 42},
+		Internal:  "internal",
+		Debug:     true,
+	},
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	var buf bytes.Buffer
+	if err := gotype.Fold(v, json.NewVisitor(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestFoldMatchesReflection(t *testing.T) {
+	for name, event := range testEvents {
+		event := event
+		t.Run(name, func(t *testing.T) {
+			expected := toJSON(t, (*reflEvent)(&event))
+			assert.Equal(t, expected, toJSON(t, &event))
+
+			var buf bytes.Buffer
+			if err := event.Fold(structform.EnsureExtVisitor(json.NewVisitor(&buf))); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expected, buf.String())
+		})
+	}
+}
+
+func TestFoldOptions(t *testing.T) {
+	event := testEvents["all fields"]
+	opts := []gotype.FoldOption{gotype.FoldTime(gotype.TimeEpochMillis)}
+
+	fold := func(v interface{}) string {
+		var buf bytes.Buffer
+		if err := gotype.Fold(v, json.NewVisitor(&buf), opts...); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	actual := fold(&event)
+	assert.Equal(t, fold((*reflEvent)(&event)), actual)
+	assert.Contains(t, actual, `"@timestamp":1580702706000`)
+}
+
+func TestUnfoldMatchesReflection(t *testing.T) {
+	for name, event := range testEvents {
+		event := event
+		t.Run(name, func(t *testing.T) {
+			in := toJSON(t, (*reflEvent)(&event))
+
+			var expected reflEvent
+			if err := unfoldJSON(&expected, in); err != nil {
+				t.Fatal(err)
+			}
+
+			var actual Event
+			if err := unfoldJSON(&actual, in); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, Event(expected), actual)
+		})
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	in := `{
+		"msg": "hello",
+		"count": 3,
+		"ratio": 0.1,
+		"seq": 1099511627776,
+		"span.id": 4294967295,
+		"unknown": {"a": [1, {"b": 2}]},
+		"host": {"name": "h1", "ip": ["::1"]},
+		"fields": {"a": 1}
+	}`
+
+	var event Event
+	if err := unfoldJSON(&event, in); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, Event{
+		Message:  "hello",
+		Level:    LevelInfo,
+		Count:    3,
+		Ratio:    0.1,
+		Sequence: 1099511627776,
+		Host:     &Host{Name: "h1", IP: []string{"::1"}},
+		Fields:   map[string]interface{}{"a": int64(1)},
+		Trace:    Trace{SpanID: 4294967295},
+	}, event)
+}
+
+func TestUnfoldErrors(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		opts []gotype.UnfoldOption
+	}{
+		"out of range":  {in: `{"span.id": 4294967296}`},
+		"wrong type":    {in: `{"message": true}`},
+		"missing value": {in: `{"message"}`},
+		"unknown field": {
+			in:   `{"unknown": 1}`,
+			opts: []gotype.UnfoldOption{gotype.UnfoldUnknownFields(gotype.UnknownFieldFail)},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var event Event
+			assert.Error(t, unfoldJSON(&event, test.in, test.opts...))
+		})
+	}
+}
+
+func unfoldJSON(to interface{}, in string, opts ...gotype.UnfoldOption) error {
+	u, err := gotype.NewUnfolder(to, opts...)
+	if err != nil {
+		return err
+	}
+	return json.ParseString(in, u)
+}
+
+func BenchmarkFold(b *testing.B) {
+	event := testEvents["all fields"]
+	var buf bytes.Buffer
+
+	bench := func(b *testing.B, v interface{}) {
+		it, err := gotype.NewIterator(json.NewVisitor(&buf))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			if err := it.Fold(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("generated", func(b *testing.B) { bench(b, &event) })
+	b.Run("reflection", func(b *testing.B) { bench(b, (*reflEvent)(&event)) })
+}
@@ -0,0 +1,447 @@
+// Code generated by gotypegen; DO NOT EDIT.
+
+package example
+
+import (
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/gotype"
+)
+
+// Fold folds the Event into vs.
+func (v Event) Fold(vs structform.ExtVisitor) error {
+	if err := vs.OnObjectStart(-1, structform.AnyType); err != nil {
+		return err
+	}
+	if err := vs.OnKey("@timestamp"); err != nil {
+		return err
+	}
+	if err := gotype.FoldValue(vs, v.Timestamp); err != nil {
+		return err
+	}
+	if err := vs.OnKey("message"); err != nil {
+		return err
+	}
+	if err := vs.OnString(v.Message); err != nil {
+		return err
+	}
+	if err := vs.OnKey("level"); err != nil {
+		return err
+	}
+	if err := gotype.FoldValue(vs, v.Level); err != nil {
+		return err
+	}
+	if err := vs.OnKey("count"); err != nil {
+		return err
+	}
+	if err := vs.OnInt64(int64(v.Count)); err != nil {
+		return err
+	}
+	if err := vs.OnKey("seq"); err != nil {
+		return err
+	}
+	if err := vs.OnUint64(v.Sequence); err != nil {
+		return err
+	}
+	if err := vs.OnKey("ratio"); err != nil {
+		return err
+	}
+	if err := vs.OnFloat32(v.Ratio); err != nil {
+		return err
+	}
+	if err := vs.OnKey("success"); err != nil {
+		return err
+	}
+	if err := vs.OnBool(v.Success); err != nil {
+		return err
+	}
+	if len(v.Tags) > 0 {
+		if err := vs.OnKey("tags"); err != nil {
+			return err
+		}
+		if err := vs.OnStringArray(v.Tags); err != nil {
+			return err
+		}
+	}
+	if len(v.Labels) > 0 {
+		if err := vs.OnKey("labels"); err != nil {
+			return err
+		}
+		if err := vs.OnStringObject(v.Labels); err != nil {
+			return err
+		}
+	}
+	if v.Host != nil {
+		if err := vs.OnKey("host"); err != nil {
+			return err
+		}
+		if err := (*v.Host).Fold(vs); err != nil {
+			return err
+		}
+	}
+	if err := vs.OnKey("source"); err != nil {
+		return err
+	}
+	if err := v.Source.Fold(vs); err != nil {
+		return err
+	}
+	if err := vs.OnKey("duration"); err != nil {
+		return err
+	}
+	if v.Duration == nil {
+		if err := vs.OnNil(); err != nil {
+			return err
+		}
+	} else {
+		if err := vs.OnInt64(*v.Duration); err != nil {
+			return err
+		}
+	}
+	if v.Fields != nil {
+		if err := vs.OnKey("fields"); err != nil {
+			return err
+		}
+		if err := gotype.FoldValue(vs, v.Fields); err != nil {
+			return err
+		}
+	}
+	if len(v.Trace.TraceID) > 0 {
+		if err := vs.OnKey("trace.id"); err != nil {
+			return err
+		}
+		if err := vs.OnString(v.Trace.TraceID); err != nil {
+			return err
+		}
+	}
+	if err := vs.OnKey("span.id"); err != nil {
+		return err
+	}
+	if err := vs.OnUint32(v.Trace.SpanID); err != nil {
+		return err
+	}
+	return vs.OnObjectFinished()
+}
+
+// Expand returns the UnfoldState for unfolding into the Event.
+func (v *Event) Expand() gotype.UnfoldState {
+	return &eventUnfoldState{to: v, field: -2}
+}
+
+// eventUnfoldState unfolds an object into Event. field is -2 before the object
+// start, -1 while waiting for the next key, or the index of the current field.
+type eventUnfoldState struct {
+	gotype.BaseUnfoldState
+	to    *Event
+	field int
+}
+
+func (s *eventUnfoldState) OnNil(ctx gotype.UnfoldCtx) error {
+	return s.value(ctx).OnNil(ctx)
+}
+
+func (s *eventUnfoldState) OnBool(ctx gotype.UnfoldCtx, v bool) error {
+	switch s.field {
+	case 6:
+		s.to.Success = v
+		s.field = -1
+		return nil
+	}
+	return s.value(ctx).OnBool(ctx, v)
+}
+
+func (s *eventUnfoldState) OnString(ctx gotype.UnfoldCtx, v string) error {
+	switch s.field {
+	case 1:
+		s.to.Message = v
+		s.field = -1
+		return nil
+	case 13:
+		s.to.Trace.TraceID = v
+		s.field = -1
+		return nil
+	}
+	return s.value(ctx).OnString(ctx, v)
+}
+
+func (s *eventUnfoldState) OnInt(ctx gotype.UnfoldCtx, v int64) error {
+	switch s.field {
+	case 3:
+		if x := int(v); int64(x) == v {
+			s.to.Count = x
+			s.field = -1
+			return nil
+		}
+	case 4:
+		if x := uint64(v); v >= 0 && int64(x) == v {
+			s.to.Sequence = x
+			s.field = -1
+			return nil
+		}
+	case 14:
+		if x := uint32(v); v >= 0 && int64(x) == v {
+			s.to.Trace.SpanID = x
+			s.field = -1
+			return nil
+		}
+	}
+	return s.value(ctx).OnInt(ctx, v)
+}
+
+func (s *eventUnfoldState) OnUint(ctx gotype.UnfoldCtx, v uint64) error {
+	switch s.field {
+	case 3:
+		if x := int(v); x >= 0 && uint64(x) == v {
+			s.to.Count = x
+			s.field = -1
+			return nil
+		}
+	case 4:
+		s.to.Sequence = v
+		s.field = -1
+		return nil
+	case 14:
+		if x := uint32(v); uint64(x) == v {
+			s.to.Trace.SpanID = x
+			s.field = -1
+			return nil
+		}
+	}
+	return s.value(ctx).OnUint(ctx, v)
+}
+
+func (s *eventUnfoldState) OnFloat(ctx gotype.UnfoldCtx, v float64) error {
+	switch s.field {
+	case 5:
+		if x := float32(v); float64(x) == v {
+			s.to.Ratio = x
+			s.field = -1
+			return nil
+		}
+	}
+	return s.value(ctx).OnFloat(ctx, v)
+}
+
+func (s *eventUnfoldState) OnArrayStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {
+	return s.value(ctx).OnArrayStart(ctx, l, bt)
+}
+
+func (s *eventUnfoldState) OnObjectStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {
+	if s.field == -2 {
+		s.field = -1
+		s.to.Defaults()
+		return nil
+	}
+	return s.value(ctx).OnObjectStart(ctx, l, bt)
+}
+
+func (s *eventUnfoldState) OnObjectFinished(ctx gotype.UnfoldCtx) error {
+	if s.field != -1 {
+		return s.BaseUnfoldState.OnObjectFinished(ctx)
+	}
+	ctx.Done()
+	return nil
+}
+
+func (s *eventUnfoldState) OnKey(ctx gotype.UnfoldCtx, key string) error {
+	if s.field != -1 {
+		return s.BaseUnfoldState.OnKey(ctx, key)
+	}
+	switch key {
+	case "@timestamp":
+		s.field = 0
+	case "message", "msg":
+		s.field = 1
+	case "level":
+		s.field = 2
+	case "count":
+		s.field = 3
+	case "seq":
+		s.field = 4
+	case "ratio":
+		s.field = 5
+	case "success":
+		s.field = 6
+	case "tags":
+		s.field = 7
+	case "labels":
+		s.field = 8
+	case "host":
+		s.field = 9
+	case "source":
+		s.field = 10
+	case "duration":
+		s.field = 11
+	case "fields":
+		s.field = 12
+	case "trace.id":
+		s.field = 13
+	case "span.id":
+		s.field = 14
+	default:
+		s.field = 15
+	}
+	return nil
+}
+
+// value pushes the UnfoldState for the current field. Unknown fields
+// are handled by gotype.
+func (s *eventUnfoldState) value(ctx gotype.UnfoldCtx) gotype.UnfoldState {
+	var to interface{}
+	switch s.field {
+	case -2, -1:
+		return &s.BaseUnfoldState
+	case 0:
+		to = &s.to.Timestamp
+	case 1:
+		to = &s.to.Message
+	case 2:
+		to = &s.to.Level
+	case 3:
+		to = &s.to.Count
+	case 4:
+		to = &s.to.Sequence
+	case 5:
+		to = &s.to.Ratio
+	case 6:
+		to = &s.to.Success
+	case 7:
+		to = &s.to.Tags
+	case 8:
+		to = &s.to.Labels
+	case 9:
+		to = &s.to.Host
+	case 10:
+		to = &s.to.Source
+	case 11:
+		to = &s.to.Duration
+	case 12:
+		to = &s.to.Fields
+	case 13:
+		to = &s.to.Trace.TraceID
+	case 14:
+		to = &s.to.Trace.SpanID
+	}
+
+	s.field = -1
+	st := gotype.ValueState(to)
+	ctx.Push(st)
+	return st
+}
+
+// Fold folds the Host into vs.
+func (v Host) Fold(vs structform.ExtVisitor) error {
+	if err := vs.OnObjectStart(-1, structform.AnyType); err != nil {
+		return err
+	}
+	if err := vs.OnKey("name"); err != nil {
+		return err
+	}
+	if err := vs.OnString(v.Name); err != nil {
+		return err
+	}
+	if len(v.IP) > 0 {
+		if err := vs.OnKey("ip"); err != nil {
+			return err
+		}
+		if err := vs.OnStringArray(v.IP); err != nil {
+			return err
+		}
+	}
+	return vs.OnObjectFinished()
+}
+
+// Expand returns the UnfoldState for unfolding into the Host.
+func (v *Host) Expand() gotype.UnfoldState {
+	return &hostUnfoldState{to: v, field: -2}
+}
+
+// hostUnfoldState unfolds an object into Host. field is -2 before the object
+// start, -1 while waiting for the next key, or the index of the current field.
+type hostUnfoldState struct {
+	gotype.BaseUnfoldState
+	to    *Host
+	field int
+}
+
+func (s *hostUnfoldState) OnNil(ctx gotype.UnfoldCtx) error {
+	return s.value(ctx).OnNil(ctx)
+}
+
+func (s *hostUnfoldState) OnBool(ctx gotype.UnfoldCtx, v bool) error {
+	return s.value(ctx).OnBool(ctx, v)
+}
+
+func (s *hostUnfoldState) OnString(ctx gotype.UnfoldCtx, v string) error {
+	switch s.field {
+	case 0:
+		s.to.Name = v
+		s.field = -1
+		return nil
+	}
+	return s.value(ctx).OnString(ctx, v)
+}
+
+func (s *hostUnfoldState) OnInt(ctx gotype.UnfoldCtx, v int64) error {
+	return s.value(ctx).OnInt(ctx, v)
+}
+
+func (s *hostUnfoldState) OnUint(ctx gotype.UnfoldCtx, v uint64) error {
+	return s.value(ctx).OnUint(ctx, v)
+}
+
+func (s *hostUnfoldState) OnFloat(ctx gotype.UnfoldCtx, v float64) error {
+	return s.value(ctx).OnFloat(ctx, v)
+}
+
+func (s *hostUnfoldState) OnArrayStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {
+	return s.value(ctx).OnArrayStart(ctx, l, bt)
+}
+
+func (s *hostUnfoldState) OnObjectStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {
+	if s.field == -2 {
+		s.field = -1
+		return nil
+	}
+	return s.value(ctx).OnObjectStart(ctx, l, bt)
+}
+
+func (s *hostUnfoldState) OnObjectFinished(ctx gotype.UnfoldCtx) error {
+	if s.field != -1 {
+		return s.BaseUnfoldState.OnObjectFinished(ctx)
+	}
+	ctx.Done()
+	return nil
+}
+
+func (s *hostUnfoldState) OnKey(ctx gotype.UnfoldCtx, key string) error {
+	if s.field != -1 {
+		return s.BaseUnfoldState.OnKey(ctx, key)
+	}
+	switch key {
+	case "name":
+		s.field = 0
+	case "ip":
+		s.field = 1
+	default:
+		s.field = 2
+	}
+	return nil
+}
+
+// value pushes the UnfoldState for the current field. Unknown fields
+// are handled by gotype.
+func (s *hostUnfoldState) value(ctx gotype.UnfoldCtx) gotype.UnfoldState {
+	var to interface{}
+	switch s.field {
+	case -2, -1:
+		return &s.BaseUnfoldState
+	case 0:
+		to = &s.to.Name
+	case 1:
+		to = &s.to.IP
+	}
+
+	s.field = -1
+	st := gotype.ValueState(to)
+	ctx.Push(st)
+	return st
+}
@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package example demonstrates the code generated by gotypegen.
+package example
+
+import "time"
+
+//go:generate go run github.com/elastic/go-structform/gotype/cmd/gotypegen
+
+// Event is folded and unfolded by the generated code.
+//
+//gotype:generate
+type Event struct {
+	Timestamp time.Time         `struct:"@timestamp"`
+	Message   string            `struct:"message,alias=msg"`
+	Level     Level             `struct:"level"`
+	Count     int               `struct:"count"`
+	Sequence  uint64            `struct:"seq"`
+	Ratio     float32           `struct:"ratio"`
+	Success   bool              `struct:"success"`
+	Tags      []string          `struct:"tags,omitempty"`
+	Labels    map[string]string `struct:"labels,omitempty"`
+	Host      *Host             `struct:"host,omitempty"`
+	Source    Host              `struct:"source"`
+	Duration  *int64            `struct:"duration"`
+	Fields    interface{}       `struct:"fields,omitempty"`
+	Trace     `struct:",inline"`
+
+	Internal string `struct:"-"`
+	Debug    bool   `struct:",omit"`
+	private  int
+}
+
+// Host describes the source of an Event.
+//
+//gotype:generate
+type Host struct {
+	Name string   `struct:"name"`
+	IP   []string `struct:"ip,omitempty"`
+}
+
+// Trace is inlined into Event.
+type Trace struct {
+	TraceID string `struct:"trace.id,omitempty"`
+	SpanID  uint32 `struct:"span.id"`
+}
+
+// Level is the severity of an Event.
+type Level uint8
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+)
+
+// Defaults sets the level of events unfolded without level.
+func (e *Event) Defaults() {
+	e.Level = LevelInfo
+}
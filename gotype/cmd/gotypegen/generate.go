@@ -0,0 +1,765 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-structform/gotype"
+)
+
+type config struct {
+	// output file name, excluded from parsing
+	output string
+
+	// struct tag names, the first tag found is used
+	tags []string
+
+	// naming derives document names from go names for fields without tag
+	// name
+	naming gotype.NamingStrategy
+}
+
+type generator struct {
+	config
+
+	fset    *token.FileSet
+	pkg     string
+	types   map[string]*ast.TypeSpec
+	methods map[string]map[string]*ast.FuncDecl
+
+	// targets lists the annotated structs in source order
+	targets []*ast.TypeSpec
+
+	buf bytes.Buffer
+}
+
+// field is a document field. Fields of squashed structs are flattened into
+// the parent.
+type field struct {
+	name      string
+	aliases   []string
+	path      string // selector of the go field, relative to the struct
+	typ       ast.Expr
+	omitEmpty bool
+}
+
+type tagOptions struct {
+	name      string
+	squash    bool
+	omitEmpty bool
+	omit      bool
+	aliases   []string
+
+	// unsupported records the first tag option requiring reflection
+	unsupported string
+}
+
+const annotation = "//gotype:generate"
+
+// primitives maps builtin types to the visitor method suffix used for
+// folding values, slices and maps with string keys.
+var primitives = map[string]string{
+	"bool":    "Bool",
+	"string":  "String",
+	"int":     "Int",
+	"int8":    "Int8",
+	"int16":   "Int16",
+	"int32":   "Int32",
+	"rune":    "Int32",
+	"int64":   "Int64",
+	"uint":    "Uint",
+	"uint8":   "Uint8",
+	"byte":    "Uint8",
+	"uint16":  "Uint16",
+	"uint32":  "Uint32",
+	"uint64":  "Uint64",
+	"float32": "Float32",
+	"float64": "Float64",
+}
+
+var (
+	signedTypes   = map[string]bool{"int": true, "int8": true, "int16": true, "int32": true, "rune": true}
+	unsignedTypes = map[string]bool{"uint": true, "uint8": true, "byte": true, "uint16": true, "uint32": true}
+)
+
+func generate(dir string, cfg config) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && name != cfg.output
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in '%v', found %v", dir, len(pkgs))
+	}
+
+	g := &generator{
+		config:  cfg,
+		fset:    fset,
+		types:   map[string]*ast.TypeSpec{},
+		methods: map[string]map[string]*ast.FuncDecl{},
+	}
+	for name, pkg := range pkgs {
+		g.pkg = name
+
+		files := make([]string, 0, len(pkg.Files))
+		for file := range pkg.Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			if err := g.collect(pkg.Files[file]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(g.targets) == 0 {
+		return nil, fmt.Errorf("no struct annotated with '%v' found in '%v'", annotation, dir)
+	}
+	return g.generate()
+}
+
+// collect records the type declarations, methods and annotated structs of
+// a file.
+func (g *generator) collect(f *ast.File) error {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				g.types[ts.Name.Name] = ts
+
+				annotated := isAnnotated(ts.Doc) || (!d.Lparen.IsValid() && isAnnotated(d.Doc))
+				if !annotated {
+					continue
+				}
+				if _, ok := ts.Type.(*ast.StructType); !ok || ts.Assign.IsValid() {
+					return fmt.Errorf("%v: %v is annotated, but is no struct type",
+						g.fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				g.targets = append(g.targets, ts)
+			}
+
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) != 1 {
+				continue
+			}
+
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				if g.methods[id.Name] == nil {
+					g.methods[id.Name] = map[string]*ast.FuncDecl{}
+				}
+				g.methods[id.Name][d.Name.Name] = d
+			}
+		}
+	}
+	return nil
+}
+
+func isAnnotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == annotation {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.printf("// Code generated by gotypegen; DO NOT EDIT.\n\n")
+	g.printf("package %v\n\n", g.pkg)
+	g.printf("import (\n")
+	g.printf("structform %q\n", "github.com/elastic/go-structform")
+	g.printf("%q\n", "github.com/elastic/go-structform/gotype")
+	g.printf(")\n")
+
+	for _, ts := range g.targets {
+		name := ts.Name.Name
+		for _, method := range []string{"Fold", "Expand", "value"} {
+			if g.methods[name][method] != nil {
+				return nil, fmt.Errorf("%v: method %v.%v conflicts with generated code",
+					g.fset.Position(ts.Pos()), name, method)
+			}
+		}
+
+		fields, err := g.structFields(name, ts.Type.(*ast.StructType), "", map[string]bool{name: true})
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", g.fset.Position(ts.Pos()), err)
+		}
+		if err := checkDuplicates(name, fields); err != nil {
+			return nil, fmt.Errorf("%v: %v", g.fset.Position(ts.Pos()), err)
+		}
+
+		if err := g.genFold(name, fields); err != nil {
+			return nil, fmt.Errorf("%v: %v", g.fset.Position(ts.Pos()), err)
+		}
+		g.genUnfold(name, fields)
+	}
+
+	return format.Source(g.buf.Bytes())
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// structFields returns the document fields of a struct. Fields of squashed
+// structs are added recursively, seen guards against recursive types.
+func (g *generator) structFields(
+	typeName string,
+	st *ast.StructType,
+	path string,
+	seen map[string]bool,
+) ([]field, error) {
+	var fields []field
+
+	for _, f := range st.Fields.List {
+		var names []string
+		if len(f.Names) == 0 {
+			names = []string{embeddedName(f.Type)}
+		} else {
+			for _, id := range f.Names {
+				names = append(names, id.Name)
+			}
+		}
+
+		tag := ""
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = g.lookupTag(reflect.StructTag(raw))
+		}
+		opts := parseTag(tag)
+
+		for _, name := range names {
+			if !ast.IsExported(name) || opts.omit {
+				continue
+			}
+
+			if opts.unsupported != "" {
+				return nil, fmt.Errorf("field %v.%v: tag option '%v' requires reflection",
+					typeName, name, opts.unsupported)
+			}
+			if opts.squash && opts.omitEmpty {
+				return nil, fmt.Errorf("field %v.%v: inline and omitempty must not be set at the same time",
+					typeName, name)
+			}
+
+			if opts.squash {
+				inline, err := g.squashFields(typeName, name, f.Type, path+name+".", seen)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, inline...)
+				continue
+			}
+
+			docName := opts.name
+			if docName == "" {
+				docName = g.naming(name)
+			}
+			fields = append(fields, field{
+				name:      docName,
+				aliases:   opts.aliases,
+				path:      path + name,
+				typ:       f.Type,
+				omitEmpty: opts.omitEmpty,
+			})
+		}
+	}
+
+	return fields, nil
+}
+
+func (g *generator) squashFields(
+	typeName, name string,
+	typ ast.Expr,
+	path string,
+	seen map[string]bool,
+) ([]field, error) {
+	id, ok := typ.(*ast.Ident)
+	var st *ast.StructType
+	if ok && g.types[id.Name] != nil {
+		st, _ = g.types[id.Name].Type.(*ast.StructType)
+	}
+	if st == nil {
+		return nil, fmt.Errorf("field %v.%v: squash requires a struct type declared in package %v",
+			typeName, name, g.pkg)
+	}
+
+	if seen[id.Name] {
+		return nil, fmt.Errorf("field %v.%v: recursive squash of %v", typeName, name, id.Name)
+	}
+	seen[id.Name] = true
+	defer delete(seen, id.Name)
+
+	return g.structFields(id.Name, st, path, seen)
+}
+
+func embeddedName(t ast.Expr) string {
+	switch v := t.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.StarExpr:
+		return embeddedName(v.X)
+	case *ast.SelectorExpr:
+		return v.Sel.Name
+	}
+	return ""
+}
+
+func (g *generator) lookupTag(tag reflect.StructTag) string {
+	for _, name := range g.tags {
+		if s, ok := tag.Lookup(name); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// parseTag parses the tag options like gotype does.
+func parseTag(tag string) tagOptions {
+	s := strings.Split(tag, ",")
+	if s[0] == "-" {
+		return tagOptions{omit: true}
+	}
+
+	opts := tagOptions{name: strings.TrimSpace(s[0])}
+	for _, opt := range s[1:] {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "squash" || opt == "inline":
+			opts.squash = true
+		case opt == "omitempty":
+			opts.omitEmpty = true
+		case opt == "omit":
+			opts.omit = true
+		case opt == "remain" || opt == "required" || opt == "string" || strings.HasPrefix(opt, "default="):
+			if opts.unsupported == "" {
+				opts.unsupported = opt
+			}
+		case strings.HasPrefix(opt, "alias="):
+			if alias := strings.TrimPrefix(opt, "alias="); alias != "" {
+				opts.aliases = append(opts.aliases, alias)
+			}
+		}
+	}
+	return opts
+}
+
+func checkDuplicates(typeName string, fields []field) error {
+	names := map[string]bool{}
+	for _, f := range fields {
+		for _, name := range append([]string{f.name}, f.aliases...) {
+			if names[name] {
+				return fmt.Errorf("%v: duplicate field name '%v'", typeName, name)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// builtin returns the name of a builtin type not shadowed by a type declared
+// in the package.
+func (g *generator) builtin(t ast.Expr) (string, bool) {
+	id, ok := t.(*ast.Ident)
+	if !ok || g.types[id.Name] != nil {
+		return "", false
+	}
+	if _, exists := primitives[id.Name]; !exists {
+		return "", false
+	}
+	return id.Name, true
+}
+
+// foldCall returns the call folding x without reflection, if the type of x
+// is supported.
+func (g *generator) foldCall(t ast.Expr, x string) (string, bool) {
+	if name, ok := g.builtin(t); ok {
+		if name == "int" {
+			// gotype folds int as int64
+			return fmt.Sprintf("vs.OnInt64(int64(%v))", x), true
+		}
+		return fmt.Sprintf("vs.On%v(%v)", primitives[name], x), true
+	}
+
+	switch v := t.(type) {
+	case *ast.Ident:
+		if g.isTarget(v.Name) {
+			if strings.HasPrefix(x, "*") {
+				x = "(" + x + ")"
+			}
+			return fmt.Sprintf("%v.Fold(vs)", x), true
+		}
+
+	case *ast.ArrayType:
+		if name, ok := g.builtin(v.Elt); ok && v.Len == nil {
+			return fmt.Sprintf("vs.On%vArray(%v)", primitives[name], x), true
+		}
+
+	case *ast.MapType:
+		if key, ok := g.builtin(v.Key); ok && key == "string" {
+			if name, ok := g.builtin(v.Value); ok {
+				return fmt.Sprintf("vs.On%vObject(%v)", primitives[name], x), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (g *generator) isTarget(name string) bool {
+	for _, ts := range g.targets {
+		if ts.Name.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isDirect checks if values of type t are folded without reflection.
+func (g *generator) isDirect(t ast.Expr) bool {
+	if star, ok := t.(*ast.StarExpr); ok {
+		return g.isDirect(star.X)
+	}
+	_, ok := g.foldCall(t, "")
+	return ok
+}
+
+func (g *generator) foldValue(t ast.Expr, x string) {
+	if call, ok := g.foldCall(t, x); ok {
+		g.printf("if err := %v; err != nil {\nreturn err\n}\n", call)
+		return
+	}
+
+	if star, ok := t.(*ast.StarExpr); ok && g.isDirect(star.X) {
+		g.printf("if %v == nil {\n", x)
+		g.printf("if err := vs.OnNil(); err != nil {\nreturn err\n}\n")
+		g.printf("} else {\n")
+		g.foldValue(star.X, "*"+x)
+		g.printf("}\n")
+		return
+	}
+
+	g.printf("if err := gotype.FoldValue(vs, %v); err != nil {\nreturn err\n}\n", x)
+}
+
+// nonEmpty returns the conditions for folding a field with omitempty, and
+// the type and expression of the value to fold. Like gotype, pointers,
+// interfaces, strings, slices, arrays and maps are checked only.
+func (g *generator) nonEmpty(t ast.Expr, x string) ([]string, ast.Expr, string, error) {
+	var conds []string
+	for {
+		star, ok := t.(*ast.StarExpr)
+		if !ok {
+			break
+		}
+		conds = append(conds, x+" != nil")
+		t, x = star.X, "*"+x
+	}
+
+	switch g.emptyKind(t, map[string]bool{}) {
+	case "len":
+		conds = append(conds, fmt.Sprintf("len(%v) > 0", x))
+	case "nil":
+		conds = append(conds, x+" != nil")
+	case "unknown":
+		return nil, nil, "", fmt.Errorf("can not resolve type %v required by omitempty", types.ExprString(t))
+	}
+	return conds, t, x, nil
+}
+
+func (g *generator) emptyKind(t ast.Expr, seen map[string]bool) string {
+	switch v := t.(type) {
+	case *ast.Ident:
+		if name, ok := g.builtin(v); ok {
+			if name == "string" {
+				return "len"
+			}
+			return ""
+		}
+		if v.Name == "error" {
+			return "nil"
+		}
+		if ts := g.types[v.Name]; ts != nil && !seen[v.Name] {
+			seen[v.Name] = true
+			return g.emptyKind(ts.Type, seen)
+		}
+		return "unknown"
+	case *ast.StarExpr, *ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType, *ast.MapType:
+		return "len"
+	case *ast.StructType, *ast.ChanType, *ast.FuncType:
+		return ""
+	}
+	return "unknown"
+}
+
+func (g *generator) genFold(name string, fields []field) error {
+	count := len(fields)
+	for _, f := range fields {
+		if f.omitEmpty {
+			count = -1
+		}
+	}
+
+	g.printf("\n// Fold folds the %v into vs.\n", name)
+	g.printf("func (v %v) Fold(vs structform.ExtVisitor) error {\n", name)
+	g.printf("if err := vs.OnObjectStart(%v, structform.AnyType); err != nil {\nreturn err\n}\n", count)
+
+	for _, f := range fields {
+		x := "v." + f.path
+		if !f.omitEmpty {
+			g.printf("if err := vs.OnKey(%q); err != nil {\nreturn err\n}\n", f.name)
+			g.foldValue(f.typ, x)
+			continue
+		}
+
+		conds, t, x, err := g.nonEmpty(f.typ, x)
+		if err != nil {
+			return fmt.Errorf("field %v.%v: %v", name, f.path, err)
+		}
+		if len(conds) == 0 {
+			conds = []string{"true"}
+		}
+
+		g.printf("if %v {\n", strings.Join(conds, " && "))
+		g.printf("if err := vs.OnKey(%q); err != nil {\nreturn err\n}\n", f.name)
+		g.foldValue(t, x)
+		g.printf("}\n")
+	}
+
+	g.printf("return vs.OnObjectFinished()\n")
+	g.printf("}\n")
+	return nil
+}
+
+// assignment renders the unfolding of a primitive event into a builtin
+// field without reflection. Values requiring conversion are assigned only if
+// the conversion does not change the value.
+type assignment func(typ string) (cond string, ok bool)
+
+var assignments = map[string]assignment{
+	"OnBool": func(typ string) (string, bool) {
+		return "", typ == "bool"
+	},
+	"OnString": func(typ string) (string, bool) {
+		return "", typ == "string"
+	},
+	"OnInt": func(typ string) (string, bool) {
+		switch {
+		case typ == "int64":
+			return "", true
+		case signedTypes[typ]:
+			return "int64(x) == v", true
+		case unsignedTypes[typ] || typ == "uint64":
+			return "v >= 0 && int64(x) == v", true
+		}
+		return "", false
+	},
+	"OnUint": func(typ string) (string, bool) {
+		switch {
+		case typ == "uint64":
+			return "", true
+		case unsignedTypes[typ]:
+			return "uint64(x) == v", true
+		case signedTypes[typ] || typ == "int64":
+			return "x >= 0 && uint64(x) == v", true
+		}
+		return "", false
+	},
+	"OnFloat": func(typ string) (string, bool) {
+		switch typ {
+		case "float64":
+			return "", true
+		case "float32":
+			return "float64(x) == v", true
+		}
+		return "", false
+	},
+}
+
+func (g *generator) genUnfold(name string, fields []field) {
+	state := gotype.NameCamelCase(name) + "UnfoldState"
+
+	g.printf("\n// Expand returns the UnfoldState for unfolding into the %v.\n", name)
+	g.printf("func (v *%v) Expand() gotype.UnfoldState {\n", name)
+	g.printf("return &%v{to: v, field: -2}\n", state)
+	g.printf("}\n")
+
+	g.printf("\n// %v unfolds an object into %v. field is -2 before the object\n", state, name)
+	g.printf("// start, -1 while waiting for the next key, or the index of the current field.\n")
+	g.printf("type %v struct {\n", state)
+	g.printf("gotype.BaseUnfoldState\n")
+	g.printf("to *%v\n", name)
+	g.printf("field int\n")
+	g.printf("}\n")
+
+	// primitive events
+	events := []struct{ name, typ string }{
+		{"OnNil", ""},
+		{"OnBool", "bool"},
+		{"OnString", "string"},
+		{"OnInt", "int64"},
+		{"OnUint", "uint64"},
+		{"OnFloat", "float64"},
+	}
+	for _, ev := range events {
+		if ev.typ == "" {
+			g.printf("\nfunc (s *%v) %v(ctx gotype.UnfoldCtx) error {\n", state, ev.name)
+			g.printf("return s.value(ctx).%v(ctx)\n", ev.name)
+			g.printf("}\n")
+			continue
+		}
+
+		g.printf("\nfunc (s *%v) %v(ctx gotype.UnfoldCtx, v %v) error {\n", state, ev.name, ev.typ)
+		g.genAssignments(fields, assignments[ev.name])
+		g.printf("return s.value(ctx).%v(ctx, v)\n", ev.name)
+		g.printf("}\n")
+	}
+
+	g.printf("\nfunc (s *%v) OnArrayStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {\n", state)
+	g.printf("return s.value(ctx).OnArrayStart(ctx, l, bt)\n")
+	g.printf("}\n")
+
+	g.printf("\nfunc (s *%v) OnObjectStart(ctx gotype.UnfoldCtx, l int, bt structform.BaseType) error {\n", state)
+	g.printf("if s.field == -2 {\n")
+	g.printf("s.field = -1\n")
+	if g.hasDefaults(name) {
+		g.printf("s.to.Defaults()\n")
+	}
+	g.printf("return nil\n")
+	g.printf("}\n")
+	g.printf("return s.value(ctx).OnObjectStart(ctx, l, bt)\n")
+	g.printf("}\n")
+
+	g.printf("\nfunc (s *%v) OnObjectFinished(ctx gotype.UnfoldCtx) error {\n", state)
+	g.printf("if s.field != -1 {\n")
+	g.printf("return s.BaseUnfoldState.OnObjectFinished(ctx)\n")
+	g.printf("}\n")
+	g.printf("ctx.Done()\n")
+	g.printf("return nil\n")
+	g.printf("}\n")
+
+	g.printf("\nfunc (s *%v) OnKey(ctx gotype.UnfoldCtx, key string) error {\n", state)
+	g.printf("if s.field != -1 {\n")
+	g.printf("return s.BaseUnfoldState.OnKey(ctx, key)\n")
+	g.printf("}\n")
+	g.printf("switch key {\n")
+	for i, f := range fields {
+		keys := make([]string, 0, len(f.aliases)+1)
+		for _, key := range append([]string{f.name}, f.aliases...) {
+			keys = append(keys, strconv.Quote(key))
+		}
+		g.printf("case %v:\n", strings.Join(keys, ", "))
+		g.printf("s.field = %v\n", i)
+	}
+	g.printf("default:\n")
+	g.printf("s.field = %v\n", len(fields))
+	g.printf("}\n")
+	g.printf("return nil\n")
+	g.printf("}\n")
+
+	g.printf("\n// value pushes the UnfoldState for the current field. Unknown fields\n")
+	g.printf("// are handled by gotype.\n")
+	g.printf("func (s *%v) value(ctx gotype.UnfoldCtx) gotype.UnfoldState {\n", state)
+	g.printf("var to interface{}\n")
+	g.printf("switch s.field {\n")
+	g.printf("case -2, -1:\n")
+	g.printf("return &s.BaseUnfoldState\n")
+	for i, f := range fields {
+		g.printf("case %v:\n", i)
+		g.printf("to = &s.to.%v\n", f.path)
+	}
+	g.printf("}\n\n")
+	g.printf("s.field = -1\n")
+	g.printf("st := gotype.ValueState(to)\n")
+	g.printf("ctx.Push(st)\n")
+	g.printf("return st\n")
+	g.printf("}\n")
+}
+
+func (g *generator) genAssignments(fields []field, assign assignment) {
+	var cases bytes.Buffer
+	for i, f := range fields {
+		typ, ok := g.builtin(f.typ)
+		if !ok {
+			continue
+		}
+		cond, ok := assign(typ)
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(&cases, "case %v:\n", i)
+		if cond == "" {
+			fmt.Fprintf(&cases, "s.to.%v = v\n", f.path)
+			fmt.Fprintf(&cases, "s.field = -1\n")
+			fmt.Fprintf(&cases, "return nil\n")
+			continue
+		}
+
+		fmt.Fprintf(&cases, "if x := %v(v); %v {\n", typ, cond)
+		fmt.Fprintf(&cases, "s.to.%v = x\n", f.path)
+		fmt.Fprintf(&cases, "s.field = -1\n")
+		fmt.Fprintf(&cases, "return nil\n")
+		fmt.Fprintf(&cases, "}\n")
+	}
+
+	if cases.Len() > 0 {
+		g.printf("switch s.field {\n")
+		g.buf.Write(cases.Bytes())
+		g.printf("}\n")
+	}
+}
+
+func (g *generator) hasDefaults(name string) bool {
+	fn := g.methods[name]["Defaults"]
+	return fn != nil && fn.Type.Params.NumFields() == 0 && fn.Type.Results.NumFields() == 0
+}
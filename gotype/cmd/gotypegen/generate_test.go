@@ -0,0 +1,137 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-structform/gotype"
+)
+
+var defaultConfig = config{
+	output: "gotype_generated.go",
+	tags:   []string{"struct"},
+	naming: gotype.NameLowerCase,
+}
+
+func TestGenerateExample(t *testing.T) {
+	expected, err := ioutil.ReadFile(filepath.Join("example", defaultConfig.output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := generate("example", defaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), string(actual), "run 'go generate ./example' to update the example")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := map[string]string{
+		"no annotation": `
+			type T struct{ A int }`,
+		"no struct": `
+			//gotype:generate
+			type T int`,
+		"remain": `
+			//gotype:generate
+			type T struct{ A map[string]int ` + "`struct:\",remain\"`" + ` }`,
+		"required": `
+			//gotype:generate
+			type T struct{ A int ` + "`struct:\",required\"`" + ` }`,
+		"squash non-local": `
+			//gotype:generate
+			type T struct{ A map[string]int ` + "`struct:\",squash\"`" + ` }`,
+		"squash and omitempty": `
+			type S struct{ B int }
+			//gotype:generate
+			type T struct{ A S ` + "`struct:\",squash,omitempty\"`" + ` }`,
+		"duplicate name": `
+			//gotype:generate
+			type T struct {
+				A int
+				B int ` + "`struct:\"a\"`" + `
+			}`,
+		"omitempty unknown type": `
+			import "time"
+			//gotype:generate
+			type T struct{ A time.Time ` + "`struct:\",omitempty\"`" + ` }`,
+		"method conflict": `
+			//gotype:generate
+			type T struct{ A int }
+			func (T) Fold() {}`,
+	}
+
+	for name, src := range tests {
+		src := src
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gotypegen")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			content := []byte("package test\n" + src + "\n")
+			if err := ioutil.WriteFile(filepath.Join(dir, "test.go"), content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = generate(dir, defaultConfig)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotypegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package test
+
+//gotype:generate
+type T struct {
+	HTTPServer string
+	Tagged     string ` + "`json:\"json_name\" struct:\"struct_name\"`" + `
+	Omitted    string ` + "`json:\"-\"`" + `
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := defaultConfig
+	cfg.tags = []string{"json", "struct"}
+	cfg.naming = gotype.NameSnakeCase
+	code, err := generate(dir, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, string(code), `vs.OnKey("http_server")`)
+	assert.Contains(t, string(code), `vs.OnKey("json_name")`)
+	assert.NotContains(t, string(code), "Omitted")
+}
@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Command gotypegen generates reflection free folders and unfolders for go
+// structs.
+//
+// Structs annotated with a `//gotype:generate` comment get a Fold method
+// implementing gotype.Folder and an Expand method implementing
+// gotype.Expander. The generated code honors the `squash`/`inline`,
+// `omitempty`, `omit` and `alias=` tag options. Values of fields the
+// generator can not handle directly are passed to gotype.FoldValue and
+// gotype.ValueState. Struct tags requiring reflection (`remain`, `required`,
+// `default=`, `string`) are reported as errors.
+//
+// Values passed to gotype.FoldValue and gotype.ValueState use the options of
+// the active Iterator or Unfolder. Fields handled by the generated code
+// directly do not use type specific fold or unfold extensions, and object keys
+// are always matched case sensitive.
+//
+// Usage:
+//
+//	//go:generate go run github.com/elastic/go-structform/gotype/cmd/gotypegen -o gotype_generated.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/go-structform/gotype"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gotypegen: ")
+
+	out := flag.String("o", "gotype_generated.go", "output file name, relative to the package directory")
+	tags := flag.String("tags", "struct", "comma separated list of struct tag names")
+	naming := flag.String("naming", "lower", "naming strategy for fields without tag name (lower, asis, snake, camel)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gotypegen [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config{
+		output: filepath.Base(*out),
+		tags:   strings.Split(*tags, ","),
+	}
+
+	var ok bool
+	if cfg.naming, ok = namingStrategies[*naming]; !ok {
+		log.Fatalf("unknown naming strategy '%v'", *naming)
+	}
+
+	code, err := generate(dir, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, *out), code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var namingStrategies = map[string]gotype.NamingStrategy{
+	"lower": gotype.NameLowerCase,
+	"asis":  gotype.NameAsIs,
+	"snake": gotype.NameSnakeCase,
+	"camel": gotype.NameCamelCase,
+}
@@ -18,14 +18,38 @@
 package gotype
 
 import (
+	"encoding"
+	gojson "encoding/json"
 	"reflect"
+	"time"
 
 	structform "github.com/elastic/go-structform"
 	"github.com/elastic/go-structform/internal/unsafe"
 )
 
 type options struct {
-	tag string
+	// tags lists the struct tag names to look up, in order.
+	tags   []string
+	naming NamingStrategy
+
+	// textMarshaler enables support for encoding.TextMarshaler and
+	// encoding.TextUnmarshaler.
+	textMarshaler bool
+
+	// jsonMarshaler enables support for json.Marshaler and json.Unmarshaler.
+	jsonMarshaler bool
+
+	// caseInsensitive enables case insensitive matching of struct fields
+	caseInsensitive bool
+
+	unknownFields UnknownFieldPolicy
+
+	// numberChecks enables range and precision checks when unfolding numbers
+	numberChecks bool
+
+	// coerce enables conversions between strings, numbers and booleans when
+	// unfolding
+	coerce bool
 }
 
 var (
@@ -48,10 +72,19 @@ var (
 
 	tError = reflect.TypeOf((*error)(nil)).Elem()
 
+	tTime     = reflect.TypeOf(time.Time{})
+	tDuration = reflect.TypeOf(time.Duration(0))
+
 	tExtVisitor  = reflect.TypeOf((*structform.ExtVisitor)(nil)).Elem()
 	tFolder      = reflect.TypeOf((*Folder)(nil)).Elem()
 	tExpander    = reflect.TypeOf((*Expander)(nil)).Elem()
+	tDefaulter   = reflect.TypeOf((*Defaulter)(nil)).Elem()
 	tUnfoldState = reflect.TypeOf((*UnfoldState)(nil)).Elem()
+
+	tTextMarshaler   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	tTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	tJSONMarshaler   = reflect.TypeOf((*gojson.Marshaler)(nil)).Elem()
+	tJSONUnmarshaler = reflect.TypeOf((*gojson.Unmarshaler)(nil)).Elem()
 )
 
 func bytes2Str(b []byte) string {
@@ -17,7 +17,41 @@
 
 package gotype
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// UnfoldError is returned by the Unfolder if a structform event can not be
+// applied to the target. The error records the location of the failing value
+// in the document and the event received.
+type UnfoldError struct {
+	Err error
+
+	// Path of the failing value in the document. Object keys are separated by
+	// '.', array indices are given in brackets (e.g. `outputs[2].hosts`).
+	Path string
+
+	// Expected is the go type of the value being unfolded. Expected is nil if
+	// the type can not be determined.
+	Expected reflect.Type
+
+	// Event is the name of the visitor method that failed (e.g. "OnString").
+	Event string
+
+	// Type is the structform type of the value received.
+	Type structform.BaseType
+}
+
+// UnknownFieldsError reports the paths of all object keys not matching any
+// struct field. The error is returned if UnknownFieldCollect is configured.
+type UnknownFieldsError struct {
+	Paths []string
+}
 
 var (
 	errNotInitialized           = errors.New("Unfolder is not initialized")
@@ -44,6 +78,11 @@ var (
 	errExpectedObjectValue      = errors.New("expected object value")
 	errExpectedObjectClose      = errors.New("missing object close")
 	errInlineAndOmitEmpty       = errors.New("inline and omitempty must not be set at the same time")
+	errUnknownField             = errors.New("unknown field")
+	errRemainNeedsMap           = errors.New("remain requires map with string keys")
+	errDuplicateRemain          = errors.New("only one field can be marked with remain")
+	errMissingRequired          = errors.New("missing required field")
+	errIncompleteDocument       = errors.New("incomplete document")
 
 	errUnexpectedNil       = errors.New("unexpected nil value received")
 	errUnexpectedBool      = errors.New("unexpected bool value received")
@@ -60,3 +99,30 @@ func errTODO() error {
 func visitErrTODO(V visitor, v interface{}) error {
 	return errTODO()
 }
+
+func (e *UnfoldError) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "failed to unfold %v", e.Event)
+	if e.Type != structform.AnyType {
+		fmt.Fprintf(&sb, "(%v)", e.Type)
+	}
+	if e.Expected != nil {
+		fmt.Fprintf(&sb, " into %v", e.Expected)
+	}
+	if e.Path != "" {
+		fmt.Fprintf(&sb, " at '%v'", e.Path)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *UnfoldError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields: '%v'", strings.Join(e.Paths, "', '"))
+}
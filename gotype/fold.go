@@ -18,6 +18,8 @@
 package gotype
 
 import (
+	"encoding"
+	gojson "encoding/json"
 	"reflect"
 
 	structform "github.com/elastic/go-structform"
@@ -35,6 +37,9 @@ type Iterator struct {
 	ctx foldContext
 }
 
+// Folder is implemented by types folding themselves. The Iterator passes a
+// visitor wrapping its own visitor, such that FoldValue reuses the Iterators
+// options.
 type Folder interface {
 	Fold(structform.ExtVisitor) error
 }
@@ -53,6 +58,22 @@ func Fold(v interface{}, vs structform.Visitor, opts ...FoldOption) error {
 	return nil
 }
 
+// FoldValue folds v into vs. If vs has been passed to a Folder by an
+// Iterator, the Iterators options and type cache are reused. Otherwise a new
+// Iterator with default options is used. FoldValue allows Folder
+// implementations to fold values they do not handle themselves.
+func FoldValue(vs structform.ExtVisitor, v interface{}) error {
+	if C, ok := vs.(*foldContext); ok {
+		return foldInterfaceValue(C, v)
+	}
+
+	it, err := NewIterator(vs)
+	if err != nil {
+		return err
+	}
+	return it.Fold(v)
+}
+
 func NewIterator(vs structform.Visitor, opts ...FoldOption) (*Iterator, error) {
 	reg := newTypeFoldRegistry()
 	O, err := applyFoldOpts(opts)
@@ -60,6 +81,9 @@ func NewIterator(vs structform.Visitor, opts ...FoldOption) (*Iterator, error) {
 		return nil, err
 	}
 
+	reg.set(tTime, makeTimeFold(O.timeEnc))
+	reg.set(tDuration, makeDurationFold(O.durationEnc))
+
 	var userReg map[reflect.Type]reFoldFn
 	if O.foldFns != nil {
 		userReg = map[reflect.Type]reFoldFn{}
@@ -74,7 +98,10 @@ func NewIterator(vs structform.Visitor, opts ...FoldOption) (*Iterator, error) {
 			userReg: userReg,
 			reg:     reg,
 			opts: options{
-				tag: "struct",
+				tags:          O.tags,
+				naming:        O.naming,
+				textMarshaler: !O.ignoreTextMarshaler,
+				jsonMarshaler: O.jsonMarshaler,
 			},
 		},
 	}
@@ -99,7 +126,12 @@ func foldInterfaceValue(C *foldContext, v interface{}) error {
 	}
 
 	if f, ok := v.(Folder); ok {
-		return f.Fold(C.visitor)
+		return f.Fold(C)
+	}
+
+	if implementsMarshaler(C, v) {
+		// resolve via registry, so to give builtin and user folders precedence
+		return foldAnyReflect(C, reflect.ValueOf(v))
 	}
 
 	if tmp, f := getFoldConvert(v); f != nil {
@@ -109,6 +141,20 @@ func foldInterfaceValue(C *foldContext, v interface{}) error {
 	return foldAnyReflect(C, reflect.ValueOf(v))
 }
 
+func implementsMarshaler(C *foldContext, v interface{}) bool {
+	if C.opts.jsonMarshaler {
+		if _, ok := v.(gojson.Marshaler); ok {
+			return true
+		}
+	}
+	if C.opts.textMarshaler {
+		if _, ok := v.(encoding.TextMarshaler); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func getFoldConvert(v interface{}) (interface{}, foldFn) {
 	t := reflect.TypeOf(v)
 	cast := false
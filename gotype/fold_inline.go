@@ -28,7 +28,16 @@ import (
 // not reporting object start/end events
 func getReflectFoldMapKeys(c *foldContext, t reflect.Type) (reFoldFn, error) {
 	if t.Key().Kind() != reflect.String {
-		return nil, errMapRequiresStringKey
+		get := getTextMarshaler(c, t.Key())
+		if get == nil {
+			return nil, errMapRequiresStringKey
+		}
+
+		elemVisitor, err := getReflectFold(c, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return makeMapTextKeysFold(get, elemVisitor), nil
 	}
 
 	f := getMapInlineByPrimitiveElem(t.Elem())
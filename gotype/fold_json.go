@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	gojson "encoding/json"
+	"reflect"
+
+	"github.com/elastic/go-structform/json"
+)
+
+func getJSONMarshaler(C *foldContext, t reflect.Type) ifcAccessor {
+	if !C.opts.jsonMarshaler {
+		return nil
+	}
+	return makeIfcAccessor(t, tJSONMarshaler)
+}
+
+// makeJSONMarshalerFold creates a folder calling MarshalJSON. The JSON document
+// returned is parsed into the active visitor.
+func makeJSONMarshalerFold(get ifcAccessor) reFoldFn {
+	return func(C *foldContext, v reflect.Value) error {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return C.OnNil()
+		}
+
+		b, err := get(v).(gojson.Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		return json.Parse(b, C.visitor)
+	}
+}
@@ -21,6 +21,15 @@ import "reflect"
 
 type initFoldOptions struct {
 	foldFns map[reflect.Type]reFoldFn
+
+	timeEnc     TimeEncoding
+	durationEnc DurationEncoding
+
+	ignoreTextMarshaler bool
+	jsonMarshaler       bool
+
+	tags   []string
+	naming NamingStrategy
 }
 
 type FoldOption func(*initFoldOptions) error
@@ -56,6 +65,64 @@ func Folders(in ...interface{}) FoldOption {
 	}
 }
 
+// FoldTime configures the encoding used for time.Time values.
+// By default timestamps are folded into RFC3339 strings with nanosecond precision.
+func FoldTime(enc TimeEncoding) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.timeEnc = enc
+		return nil
+	}
+}
+
+// FoldDuration configures the encoding used for time.Duration values.
+// By default durations are folded into strings like "1m30s".
+func FoldDuration(enc DurationEncoding) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.durationEnc = enc
+		return nil
+	}
+}
+
+// FoldTextMarshaler configures support for types implementing
+// encoding.TextMarshaler. If enabled (default), values and map keys
+// implementing encoding.TextMarshaler are folded into strings.
+func FoldTextMarshaler(enabled bool) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.ignoreTextMarshaler = !enabled
+		return nil
+	}
+}
+
+// FoldJSONMarshaler configures support for types implementing json.Marshaler.
+// If enabled, the JSON document returned by MarshalJSON is parsed and passed
+// to the visitor. json.Marshaler takes precedence over
+// encoding.TextMarshaler. Support is disabled by default.
+func FoldJSONMarshaler(enabled bool) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.jsonMarshaler = enabled
+		return nil
+	}
+}
+
+// FoldTags configures the struct tags used to read field names and options.
+// If a field has multiple of the configured tags, the first tag in the list
+// is used. By default the 'struct' tag is used.
+func FoldTags(names ...string) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.tags = names
+		return nil
+	}
+}
+
+// FoldNaming configures the naming strategy for fields without name in the
+// struct tag. By default field names are converted to lower case.
+func FoldNaming(naming NamingStrategy) FoldOption {
+	return func(o *initFoldOptions) error {
+		o.naming = naming
+		return nil
+	}
+}
+
 func makeUserFoldFns(in []interface{}) (map[reflect.Type]reFoldFn, error) {
 	M := map[reflect.Type]reFoldFn{}
 
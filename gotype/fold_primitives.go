@@ -55,5 +55,9 @@ func reFoldFloat64(C *foldContext, v reflect.Value) error { return C.OnFloat64(v
 func reFoldString(C *foldContext, v reflect.Value) error  { return C.OnString(v.String()) }
 
 func reFoldFolderIfc(C *foldContext, v reflect.Value) error {
-	return v.Interface().(Folder).Fold(C.visitor)
+	if v.Kind() == reflect.Ptr && v.IsNil() && v.Type().Elem().Implements(tFolder) {
+		// Fold has a value receiver and can not be called on a nil pointer
+		return C.OnNil()
+	}
+	return v.Interface().(Folder).Fold(C)
 }
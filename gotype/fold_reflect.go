@@ -19,7 +19,6 @@ package gotype
 
 import (
 	"reflect"
-	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -46,14 +45,26 @@ func getReflectFold(c *foldContext, t reflect.Type) (reFoldFn, error) {
 		return f, nil
 	}
 
-	f = getReflectFoldPrimitive(t)
-	if f != nil {
+	if t.Implements(tFolder) {
+		f := reFoldFolderIfc
 		c.reg.set(t, f)
 		return f, nil
 	}
 
-	if t.Implements(tFolder) {
-		f := reFoldFolderIfc
+	if get := getJSONMarshaler(c, t); get != nil {
+		f := makeJSONMarshalerFold(get)
+		c.reg.set(t, f)
+		return f, nil
+	}
+
+	if get := getTextMarshaler(c, t); get != nil {
+		f := makeTextMarshalerFold(get)
+		c.reg.set(t, f)
+		return f, nil
+	}
+
+	f = getReflectFoldPrimitive(t)
+	if f != nil {
 		c.reg.set(t, f)
 		return f, nil
 	}
@@ -209,7 +220,7 @@ func buildFieldFold(C *foldContext, t reflect.Type, idx int) (reFoldFn, error) {
 		return nil, nil
 	}
 
-	tagName, tagOpts := parseTags(st.Tag.Get(C.opts.tag))
+	tagName, tagOpts := parseTags(C.opts.fieldTag(st))
 	if tagOpts.squash && tagOpts.omitEmpty {
 		return nil, errInlineAndOmitEmpty
 	}
@@ -223,19 +234,42 @@ func buildFieldFold(C *foldContext, t reflect.Type, idx int) (reFoldFn, error) {
 		return buildFieldFoldInline(C, t, idx, tagOpts.omitEmpty)
 	}
 
+	if tagOpts.remain {
+		if st.Type.Kind() != reflect.Map || st.Type.Key().Kind() != reflect.String {
+			return nil, errRemainNeedsMap
+		}
+
+		// entries of the remain map are folded into the parent object
+		elemVisitor, err := getReflectFold(C, st.Type.Elem())
+		if err != nil {
+			return nil, err
+		}
+		names := map[string]struct{}{}
+		collectFieldNames(C, t, names)
+		return makeRemainFieldFold(idx, names, elemVisitor), nil
+	}
+
 	foldT := st.Type
 	if tagOpts.omitEmpty {
 		_, foldT = baseType(st.Type)
 	}
-	valueVisitor, err := getReflectFold(C, foldT)
-	if err != nil {
-		return nil, err
+
+	var valueVisitor reFoldFn
+	if tagOpts.asString {
+		valueVisitor = getReflectFoldAsString(foldT)
+	}
+	if valueVisitor == nil {
+		var err error
+		valueVisitor, err = getReflectFold(C, foldT)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if tagName != "" {
 		name = tagName
 	} else {
-		name = strings.ToLower(name)
+		name = C.opts.fieldName(name)
 	}
 
 	if tagOpts.omitEmpty {
@@ -309,6 +343,57 @@ func makeFieldFold(name string, idx int, fn reFoldFn) (reFoldFn, error) {
 	}, nil
 }
 
+// makeRemainFieldFold folds the entries of a remain map into the parent
+// object. Entries clashing with the name of a struct field are skipped, such
+// that no key is reported twice.
+func makeRemainFieldFold(idx int, names map[string]struct{}, elemVisitor reFoldFn) reFoldFn {
+	return func(C *foldContext, v reflect.Value) error {
+		rv := v.Field(idx)
+		if rv.IsNil() {
+			return nil
+		}
+
+		for _, k := range rv.MapKeys() {
+			key := k.String()
+			if _, exists := names[key]; exists {
+				continue
+			}
+
+			if err := C.OnKey(key); err != nil {
+				return err
+			}
+			if err := elemVisitor(C, rv.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// collectFieldNames adds the object keys of the fields of t, including the
+// fields of inlined structs, to names.
+func collectFieldNames(C *foldContext, t reflect.Type, names map[string]struct{}) {
+	for i := 0; i < t.NumField(); i++ {
+		st := t.Field(i)
+		if r, _ := utf8.DecodeRuneInString(st.Name); !unicode.IsUpper(r) {
+			continue
+		}
+
+		tagName, tagOpts := parseTags(C.opts.fieldTag(st))
+		switch {
+		case tagOpts.omit, tagOpts.remain:
+		case tagOpts.squash:
+			if _, bt := baseType(st.Type); bt.Kind() == reflect.Struct {
+				collectFieldNames(C, bt, names)
+			}
+		case tagName != "":
+			names[tagName] = struct{}{}
+		default:
+			names[C.opts.fieldName(st.Name)] = struct{}{}
+		}
+	}
+}
+
 func makeFieldInlineFold(idx int, fn reFoldFn) reFoldFn {
 	return func(C *foldContext, v reflect.Value) error {
 		return fn(C, v.Field(idx))
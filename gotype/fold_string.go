@@ -0,0 +1,50 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// getReflectFoldAsString creates a folder encoding numbers and booleans as
+// strings, for fields having the `string` tag option. Nil is returned if t
+// is no number or boolean.
+func getReflectFoldAsString(t reflect.Type) reFoldFn {
+	N, bt := baseType(t)
+	if !isStringEncodable(bt.Kind()) {
+		return nil
+	}
+
+	var format func(reflect.Value) string
+	switch bt.Kind() {
+	case reflect.Bool:
+		format = func(v reflect.Value) string { return strconv.FormatBool(v.Bool()) }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		format = func(v reflect.Value) string { return strconv.FormatInt(v.Int(), 10) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		format = func(v reflect.Value) string { return strconv.FormatUint(v.Uint(), 10) }
+	default:
+		bits := bt.Bits()
+		format = func(v reflect.Value) string { return strconv.FormatFloat(v.Float(), 'g', -1, bits) }
+	}
+
+	return makePointerFold(N, func(C *foldContext, v reflect.Value) error {
+		return C.OnString(format(v))
+	})
+}
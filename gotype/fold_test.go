@@ -22,6 +22,8 @@ import (
 	gojson "encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"testing"
 	"time"
 
@@ -137,6 +139,304 @@ func TestUserFold(t *testing.T) {
 	}
 }
 
+func TestFoldTime(t *testing.T) {
+	ts := time.Date(2020, 2, 3, 4, 5, 6, 7000000, time.UTC)
+	d := 90 * time.Second
+
+	type event struct {
+		Timestamp time.Time
+		Took      time.Duration
+		Retry     *time.Duration
+	}
+
+	tests := map[string]struct {
+		in   interface{}
+		opts []FoldOption
+		want sftest.Recording
+	}{
+		"default time": {
+			in:   ts,
+			want: sftest.Recording{sftest.StringRec{"2020-02-03T04:05:06.007Z"}},
+		},
+		"time pointer": {
+			in:   &ts,
+			want: sftest.Recording{sftest.StringRec{"2020-02-03T04:05:06.007Z"}},
+		},
+		"epoch seconds": {
+			in:   ts,
+			opts: []FoldOption{FoldTime(TimeEpochSeconds)},
+			want: sftest.Recording{sftest.Int64Rec{1580702706}},
+		},
+		"epoch millis": {
+			in:   ts,
+			opts: []FoldOption{FoldTime(TimeEpochMillis)},
+			want: sftest.Recording{sftest.Int64Rec{1580702706007}},
+		},
+		"epoch nanos": {
+			in:   ts,
+			opts: []FoldOption{FoldTime(TimeEpochNanos)},
+			want: sftest.Recording{sftest.Int64Rec{1580702706007000000}},
+		},
+		"custom layout": {
+			in:   ts,
+			opts: []FoldOption{FoldTime(TimeLayout("2006-01-02"))},
+			want: sftest.Recording{sftest.StringRec{"2020-02-03"}},
+		},
+		"default duration": {
+			in:   d,
+			want: sftest.Recording{sftest.StringRec{"1m30s"}},
+		},
+		"duration nanos": {
+			in:   d,
+			opts: []FoldOption{FoldDuration(DurationNanos)},
+			want: sftest.Recording{sftest.Int64Rec{int64(d)}},
+		},
+		"struct fields": {
+			in: event{Timestamp: ts, Took: d, Retry: &d},
+			want: sftest.Obj(3, structform.AnyType,
+				"timestamp", sftest.StringRec{"2020-02-03T04:05:06.007Z"},
+				"took", sftest.StringRec{"1m30s"},
+				"retry", sftest.StringRec{"1m30s"},
+			),
+		},
+		"map values": {
+			in:   map[string]time.Time{"ts": ts},
+			opts: []FoldOption{FoldTime(TimeEpochSeconds)},
+			want: sftest.Obj(1, structform.AnyType, "ts", sftest.Int64Rec{1580702706}),
+		},
+		"user folder overrides default": {
+			in: ts,
+			opts: []FoldOption{Folders(func(t *time.Time, vs structform.ExtVisitor) error {
+				return vs.OnBool(true)
+			})},
+			want: sftest.Recording{sftest.BoolRec{true}},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var rec sftest.Recording
+			if err := Fold(test.in, &rec, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+			rec.Assert(t, test.want)
+		})
+	}
+}
+
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("info"), nil
+	case 1:
+		return []byte("debug"), nil
+	}
+	return nil, fmt.Errorf("invalid level %d", int(l))
+}
+
+func (l *textLevel) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "info":
+		*l = 0
+	case "debug":
+		*l = 1
+	default:
+		return fmt.Errorf("invalid level '%s'", b)
+	}
+	return nil
+}
+
+func TestFoldTextMarshaler(t *testing.T) {
+	ip := net.IPv4(192, 168, 0, 1)
+	n, _ := new(big.Int).SetString("18446744073709551616", 10)
+
+	type event struct {
+		Level  textLevel
+		Source net.IP
+		Count  big.Int
+		Max    *big.Int
+	}
+
+	tests := map[string]struct {
+		in   interface{}
+		opts []FoldOption
+		want sftest.Recording
+	}{
+		"primitive kind": {
+			// MarshalText takes precedence over the underlying int
+			in:   textLevel(1),
+			want: sftest.Recording{sftest.StringRec{"debug"}},
+		},
+		"slice type": {
+			in:   ip,
+			want: sftest.Recording{sftest.StringRec{"192.168.0.1"}},
+		},
+		"pointer receiver": {
+			in:   n,
+			want: sftest.Recording{sftest.StringRec{"18446744073709551616"}},
+		},
+		"struct fields": {
+			in: event{Level: 1, Source: ip, Count: *n},
+			want: sftest.Obj(4, structform.AnyType,
+				"level", sftest.StringRec{"debug"},
+				"source", sftest.StringRec{"192.168.0.1"},
+				"count", sftest.StringRec{"18446744073709551616"},
+				"max", sftest.NilRec{},
+			),
+		},
+		"array": {
+			in: []textLevel{0, 1},
+			want: sftest.Arr(2, structform.AnyType,
+				sftest.StringRec{"info"},
+				sftest.StringRec{"debug"},
+			),
+		},
+		"map keys": {
+			in:   map[textLevel]int{1: 2},
+			want: sftest.Obj(1, structform.AnyType, "debug", sftest.IntRec{2}),
+		},
+		"disabled": {
+			in:   textLevel(1),
+			opts: []FoldOption{FoldTextMarshaler(false)},
+			want: sftest.Recording{sftest.IntRec{1}},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var rec sftest.Recording
+			if err := Fold(test.in, &rec, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+			rec.Assert(t, test.want)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		var rec sftest.Recording
+		err := Fold(textLevel(5), &rec)
+		assert.EqualError(t, err, "invalid level 5")
+	})
+}
+
+type jsonPoint struct{ x, y int }
+
+func (p jsonPoint) MarshalJSON() ([]byte, error) {
+	return gojson.Marshal([]int{p.x, p.y})
+}
+
+func (p *jsonPoint) UnmarshalJSON(b []byte) error {
+	var tmp [2]int
+	if err := gojson.Unmarshal(b, &tmp); err != nil {
+		return err
+	}
+	p.x, p.y = tmp[0], tmp[1]
+	return nil
+}
+
+func TestFoldJSONMarshaler(t *testing.T) {
+	type shape struct {
+		Origin jsonPoint
+		Points []jsonPoint
+		Meta   gojson.RawMessage
+		Level  textLevel
+	}
+
+	in := shape{
+		Origin: jsonPoint{1, 2},
+		Points: []jsonPoint{{3, 4}},
+		Meta:   gojson.RawMessage(`{"a": [1, "x", null]}`),
+		Level:  1,
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := Fold(in, json.NewVisitor(buf), FoldJSONMarshaler(true)); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, `{
+		"origin": [1, 2],
+		"points": [[3, 4]],
+		"meta": {"a": [1, "x", null]},
+		"level": "debug"
+	}`, buf.String())
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var rec sftest.Recording
+		if err := Fold(jsonPoint{1, 2}, &rec); err != nil {
+			t.Fatal(err)
+		}
+		rec.Assert(t, sftest.Obj(0, structform.AnyType))
+	})
+}
+
+func TestNamingStrategy(t *testing.T) {
+	tests := []struct {
+		name, snake, camel string
+	}{
+		{"Name", "name", "name"},
+		{"UserID", "user_id", "userID"},
+		{"HTTPServer", "http_server", "httpServer"},
+		{"ID", "id", "id"},
+		{"Level2Cache", "level2_cache", "level2Cache"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.snake, NameSnakeCase(test.name))
+		assert.Equal(t, test.camel, NameCamelCase(test.name))
+	}
+}
+
+func TestFoldTagsAndNaming(t *testing.T) {
+	type config struct {
+		HTTPServer string
+		Name       string `json:"display_name"`
+		Port       int    `json:"port" struct:"listen_port"`
+		Secret     string `json:"-"`
+	}
+
+	in := config{HTTPServer: "a", Name: "b", Port: 80, Secret: "c"}
+
+	tests := map[string]struct {
+		opts []FoldOption
+		want string
+	}{
+		"default": {
+			want: `{"httpserver": "a", "name": "b", "listen_port": 80, "secret": "c"}`,
+		},
+		"json tags": {
+			opts: []FoldOption{FoldTags("json")},
+			want: `{"httpserver": "a", "display_name": "b", "port": 80}`,
+		},
+		"tag fallback": {
+			opts: []FoldOption{FoldTags("struct", "json")},
+			want: `{"httpserver": "a", "display_name": "b", "listen_port": 80}`,
+		},
+		"snake case": {
+			opts: []FoldOption{FoldNaming(NameSnakeCase)},
+			want: `{"http_server": "a", "name": "b", "listen_port": 80, "secret": "c"}`,
+		},
+		"user naming": {
+			opts: []FoldOption{FoldNaming(func(name string) string { return "x_" + name })},
+			want: `{"x_HTTPServer": "a", "x_Name": "b", "listen_port": 80, "x_Secret": "c"}`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			if err := Fold(in, json.NewVisitor(buf), test.opts...); err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, test.want, buf.String())
+		})
+	}
+}
+
 func assertJSON(t *testing.T, expected, actual string) (err error) {
 	expected, err = normalizeJSON(expected)
 	if err != nil {
@@ -412,3 +712,101 @@ func foldSamples() map[string]foldCase {
 	}
 	return m
 }
+
+func TestFoldRemain(t *testing.T) {
+	type config struct {
+		Name  string
+		Extra map[string]interface{} `struct:",remain"`
+	}
+
+	tests := map[string]struct {
+		in   interface{}
+		want string
+	}{
+		"inline extra fields": {
+			in:   config{Name: "test", Extra: map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "d"}}},
+			want: `{"name": "test", "a": 1, "b": {"c": "d"}}`,
+		},
+		"nil map": {
+			in:   config{Name: "test"},
+			want: `{"name": "test"}`,
+		},
+		"skip declared fields": {
+			in:   config{Name: "test", Extra: map[string]interface{}{"name": "dup", "a": 1}},
+			want: `{"name": "test", "a": 1}`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			if err := Fold(test.in, json.NewVisitor(buf)); err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, test.want, buf.String())
+		})
+	}
+
+	t.Run("requires map", func(t *testing.T) {
+		type invalid struct {
+			Extra []string `struct:",remain"`
+		}
+		err := Fold(invalid{}, json.NewVisitor(bytes.NewBuffer(nil)))
+		assert.Equal(t, errRemainNeedsMap, err)
+	})
+}
+
+func TestFoldStringTag(t *testing.T) {
+	type config struct {
+		Port    int      `struct:"port,string"`
+		Enabled bool     `struct:"enabled,string"`
+		Ratio   *float32 `struct:"ratio,string"`
+		Limit   *uint    `struct:"limit,string"`
+		Name    string   `struct:"name,string"`
+	}
+
+	ratio := float32(0.1)
+	in := config{Port: 9200, Enabled: true, Ratio: &ratio, Name: "test"}
+
+	buf := bytes.NewBuffer(nil)
+	if err := Fold(in, json.NewVisitor(buf)); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, `{"port": "9200", "enabled": "true", "ratio": "0.1", "limit": null, "name": "test"}`, buf.String())
+}
+
+type foldValueWrapper struct {
+	Value interface{}
+}
+
+func (w foldValueWrapper) Fold(vs structform.ExtVisitor) error {
+	if err := vs.OnObjectStart(1, structform.AnyType); err != nil {
+		return err
+	}
+	if err := vs.OnKey("wrapped"); err != nil {
+		return err
+	}
+	if err := FoldValue(vs, w.Value); err != nil {
+		return err
+	}
+	return vs.OnObjectFinished()
+}
+
+func TestFoldValue(t *testing.T) {
+	type inner struct {
+		FieldName string
+	}
+	type outer struct {
+		Ptr   *foldValueWrapper
+		Value foldValueWrapper
+	}
+
+	in := outer{Value: foldValueWrapper{Value: inner{FieldName: "test"}}}
+
+	buf := bytes.NewBuffer(nil)
+	if err := Fold(in, json.NewVisitor(buf), FoldNaming(NameSnakeCase)); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, `{"ptr": null, "value": {"wrapped": {"field_name": "test"}}}`, buf.String())
+}
@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// ifcAccessor returns a value as an interface implemented by the value or its
+// pointer.
+type ifcAccessor func(reflect.Value) interface{}
+
+// makeIfcAccessor returns a function accessing values of type t as the
+// interface type ifc. Methods implemented on the pointer receiver are
+// supported as well. Returns nil if t does not implement ifc.
+func makeIfcAccessor(t, ifc reflect.Type) ifcAccessor {
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	if t.Implements(ifc) {
+		return func(v reflect.Value) interface{} {
+			return v.Interface()
+		}
+	}
+
+	if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(ifc) {
+		return func(v reflect.Value) interface{} {
+			if !v.CanAddr() {
+				tmp := reflect.New(t).Elem()
+				tmp.Set(v)
+				v = tmp
+			}
+			return v.Addr().Interface()
+		}
+	}
+
+	return nil
+}
+
+func getTextMarshaler(C *foldContext, t reflect.Type) ifcAccessor {
+	if !C.opts.textMarshaler {
+		return nil
+	}
+	return makeIfcAccessor(t, tTextMarshaler)
+}
+
+func makeTextMarshalerFold(get ifcAccessor) reFoldFn {
+	return func(C *foldContext, v reflect.Value) error {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return C.OnNil()
+		}
+
+		b, err := get(v).(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		return C.OnStringRef(b)
+	}
+}
+
+func makeMapTextKeysFold(get ifcAccessor, elemVisitor reFoldFn) reFoldFn {
+	return func(C *foldContext, rv reflect.Value) error {
+		if rv.IsNil() || !rv.IsValid() {
+			return nil
+		}
+
+		for _, k := range rv.MapKeys() {
+			key, err := get(k).(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return err
+			}
+			if err := C.OnKeyRef(key); err != nil {
+				return err
+			}
+			if err := elemVisitor(C, rv.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
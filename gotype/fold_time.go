@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"reflect"
+	"time"
+
+	stunsafe "github.com/elastic/go-structform/internal/unsafe"
+)
+
+func makeTimeFold(enc TimeEncoding) reFoldFn {
+	if enc.isEpoch() {
+		return func(C *foldContext, v reflect.Value) error {
+			t := (*time.Time)(stunsafe.ReflValuePtr(v))
+			return C.OnInt64(enc.toEpoch(*t))
+		}
+	}
+
+	layout := enc.getLayout()
+	return func(C *foldContext, v reflect.Value) error {
+		t := (*time.Time)(stunsafe.ReflValuePtr(v))
+		return C.OnString(t.Format(layout))
+	}
+}
+
+func makeDurationFold(enc DurationEncoding) reFoldFn {
+	if enc == DurationNanos {
+		return func(C *foldContext, v reflect.Value) error {
+			return C.OnInt64(v.Int())
+		}
+	}
+
+	return func(C *foldContext, v reflect.Value) error {
+		return C.OnString(time.Duration(v.Int()).String())
+	}
+}
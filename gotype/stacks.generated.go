@@ -61,6 +61,18 @@ type structformTypeStack struct {
 	stack0  [32]structform.BaseType
 }
 
+type pathStack struct {
+	current pathElem
+	stack   []pathElem
+	stack0  [32]pathElem
+}
+
+type fieldSetStack struct {
+	current []bool
+	stack   [][]bool
+	stack0  [32][]bool
+}
+
 func (s *unfolderStack) init(v unfolder) {
 	s.current = v
 	s.stack = s.stack0[:0]
@@ -168,3 +180,39 @@ func (s *structformTypeStack) pop() structform.BaseType {
 	s.stack = s.stack[:last]
 	return old
 }
+
+func (s *pathStack) init() {
+	s.current = pathElem{}
+	s.stack = s.stack0[:0]
+}
+
+func (s *pathStack) push(v pathElem) {
+	s.stack = append(s.stack, s.current)
+	s.current = v
+}
+
+func (s *pathStack) pop() pathElem {
+	old := s.current
+	last := len(s.stack) - 1
+	s.current = s.stack[last]
+	s.stack = s.stack[:last]
+	return old
+}
+
+func (s *fieldSetStack) init() {
+	s.current = nil
+	s.stack = s.stack0[:0]
+}
+
+func (s *fieldSetStack) push(v []bool) {
+	s.stack = append(s.stack, s.current)
+	s.current = v
+}
+
+func (s *fieldSetStack) pop() []bool {
+	old := s.current
+	last := len(s.stack) - 1
+	s.current = s.stack[last]
+	s.stack = s.stack[:last]
+	return old
+}
@@ -17,12 +17,57 @@
 
 package gotype
 
-import "strings"
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the document field name from a go struct field name.
+// The strategy is used for fields without name in the struct tag.
+type NamingStrategy func(name string) string
+
+var (
+	// NameLowerCase converts the field name to lower case (default).
+	NameLowerCase NamingStrategy = strings.ToLower
+
+	// NameAsIs uses the field name unchanged.
+	NameAsIs NamingStrategy = func(name string) string { return name }
+
+	// NameSnakeCase converts the field name to snake case (e.g. `HTTPServer`
+	// becomes `http_server`).
+	NameSnakeCase NamingStrategy = toSnakeCase
+
+	// NameCamelCase converts the field name to camel case (e.g. `HTTPServer`
+	// becomes `httpServer`).
+	NameCamelCase NamingStrategy = toCamelCase
+)
+
+var defaultTagNames = []string{"struct"}
 
 type tagOptions struct {
 	squash    bool
 	omitEmpty bool
 	omit      bool
+
+	// remain marks a map field collecting all keys not matching any other
+	// field
+	remain bool
+
+	// required fields must be present when unfolding
+	required bool
+
+	// asString encodes numbers and booleans as strings
+	asString bool
+
+	// def is the literal value unfolded into the field if the field is not
+	// present. hasDef is set if the default option is given. Options are
+	// separated by commas, so the literal can not contain a comma.
+	def    string
+	hasDef bool
+
+	// aliases lists alternative field names accepted when unfolding
+	aliases []string
 }
 
 var defaultTagOptions = tagOptions{
@@ -45,14 +90,87 @@ func parseTags(tag string) (string, tagOptions) {
 
 	opts := defaultTagOptions
 	for _, opt := range s[1:] {
-		switch strings.TrimSpace(opt) {
+		opt = strings.TrimSpace(opt)
+		switch opt {
 		case "squash", "inline":
 			opts.squash = true
 		case "omitempty":
 			opts.omitEmpty = true
 		case "omit":
 			opts.omit = true
+		case "remain":
+			opts.remain = true
+		case "required":
+			opts.required = true
+		case "string":
+			opts.asString = true
+		default:
+			if def := strings.TrimPrefix(opt, "default="); def != opt {
+				opts.def, opts.hasDef = def, true
+			} else if alias := strings.TrimPrefix(opt, "alias="); alias != opt && alias != "" {
+				opts.aliases = append(opts.aliases, alias)
+			}
 		}
 	}
 	return strings.TrimSpace(s[0]), opts
 }
+
+// fieldTag returns the first tag found in st, using the configured list of
+// tag names.
+func (o *options) fieldTag(st reflect.StructField) string {
+	names := o.tags
+	if len(names) == 0 {
+		names = defaultTagNames
+	}
+
+	for _, name := range names {
+		if tag, ok := st.Tag.Lookup(name); ok {
+			return tag
+		}
+	}
+	return ""
+}
+
+// fieldName returns the document name of a struct field without tag name.
+func (o *options) fieldName(name string) string {
+	if o.naming == nil {
+		return NameLowerCase(name)
+	}
+	return o.naming(name)
+}
+
+func toSnakeCase(name string) string {
+	in := []rune(name)
+	out := make([]rune, 0, len(in)+4)
+	for i, r := range in {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := in[i-1]
+			nextLower := i+1 < len(in) && unicode.IsLower(in[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
+
+func toCamelCase(name string) string {
+	in := []rune(name)
+
+	// count leading upper case characters
+	n := 0
+	for n < len(in) && unicode.IsUpper(in[n]) {
+		n++
+	}
+
+	// keep the last upper case character of an acronym followed by a word
+	if n > 1 && n < len(in) && unicode.IsLower(in[n]) {
+		n--
+	}
+
+	for i := 0; i < n; i++ {
+		in[i] = unicode.ToLower(in[i])
+	}
+	return string(in)
+}
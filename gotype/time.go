@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import "time"
+
+// TimeEncoding configures the representation of time.Time values.
+// A timestamp is either encoded as string, using a time layout, or as integer
+// relative to the unix epoch.
+type TimeEncoding struct {
+	layout string
+	unit   time.Duration
+}
+
+// DurationEncoding configures the representation of time.Duration values.
+type DurationEncoding uint8
+
+const (
+	// DurationString encodes durations as strings like "1m30s" (default).
+	DurationString DurationEncoding = iota
+
+	// DurationNanos encodes durations as integer nanoseconds.
+	DurationNanos
+)
+
+var (
+	// TimeRFC3339Nano encodes timestamps as RFC3339 strings with nanosecond
+	// precision. This is the default encoding.
+	TimeRFC3339Nano = TimeLayout(time.RFC3339Nano)
+
+	// TimeEpochSeconds encodes timestamps as seconds since the unix epoch.
+	TimeEpochSeconds = TimeEncoding{unit: time.Second}
+
+	// TimeEpochMillis encodes timestamps as milliseconds since the unix epoch.
+	TimeEpochMillis = TimeEncoding{unit: time.Millisecond}
+
+	// TimeEpochNanos encodes timestamps as nanoseconds since the unix epoch.
+	TimeEpochNanos = TimeEncoding{unit: time.Nanosecond}
+)
+
+// TimeLayout encodes timestamps as strings, using the given layout (see
+// time.Format).
+func TimeLayout(layout string) TimeEncoding {
+	return TimeEncoding{layout: layout}
+}
+
+func (e TimeEncoding) isEpoch() bool {
+	return e.unit != 0
+}
+
+func (e TimeEncoding) getLayout() string {
+	if e.layout == "" {
+		return time.RFC3339Nano
+	}
+	return e.layout
+}
+
+func (e TimeEncoding) toEpoch(t time.Time) int64 {
+	per := int64(time.Second / e.unit)
+	return t.Unix()*per + int64(t.Nanosecond())/int64(e.unit)
+}
+
+func (e TimeEncoding) fromEpoch(v int64) time.Time {
+	per := int64(time.Second / e.unit)
+	return time.Unix(v/per, (v%per)*int64(e.unit)).UTC()
+}
+
+func (e TimeEncoding) fromEpochFloat(v float64) time.Time {
+	return time.Unix(0, int64(v*float64(e.unit))).UTC()
+}
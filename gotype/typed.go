@@ -0,0 +1,138 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"reflect"
+	"unsafe"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// TypedUnfolder unfolds documents into values of type T. The unfolder for T
+// is resolved once by NewTypedUnfolder, such that setting a new target does
+// not require any type checks.
+type TypedUnfolder[T any] struct {
+	*Unfolder
+	init func(ctx *unfoldCtx, to *T)
+}
+
+// TypedIterator folds values of type T. The folder for T is resolved once by
+// NewTypedIterator.
+type TypedIterator[T any] struct {
+	ctx  foldContext
+	fold reFoldFn
+}
+
+// UnfoldAs unfolds the document emitted by src into a new value of type T.
+// src is normally a parser, e.g.:
+//
+//	cfg, err := gotype.UnfoldAs[Config](func(vs structform.Visitor) error {
+//		return json.Parse(content, vs)
+//	})
+func UnfoldAs[T any](src func(structform.Visitor) error, opts ...UnfoldOption) (T, error) {
+	var to T
+
+	u, err := NewTypedUnfolder[T](opts...)
+	if err != nil {
+		return to, err
+	}
+
+	err = u.Unfold(&to, src)
+	return to, err
+}
+
+// NewTypedUnfolder creates a reusable Unfolder for values of type T.
+func NewTypedUnfolder[T any](opts ...UnfoldOption) (*TypedUnfolder[T], error) {
+	u, err := NewUnfolder(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tu := &TypedUnfolder[T]{Unfolder: u}
+	if _, pu := lookupGoTypeUnfolder(new(T)); pu != nil {
+		tu.init = func(ctx *unfoldCtx, to *T) {
+			pu.initState(ctx, unsafe.Pointer(to))
+		}
+		return tu, nil
+	}
+
+	ru, err := lookupReflUnfolder(&u.unfoldCtx, reflect.TypeOf((*T)(nil)), true)
+	if err != nil {
+		return nil, err
+	}
+	if ru == nil {
+		return nil, errUnsupported
+	}
+
+	tu.init = func(ctx *unfoldCtx, to *T) {
+		ru.initState(ctx, reflect.ValueOf(to))
+	}
+	return tu, nil
+}
+
+// SetTarget sets the value the next document is unfolded into. The unfolder
+// is reset if to is nil.
+func (u *TypedUnfolder[T]) SetTarget(to *T) {
+	if to == nil {
+		u.Reset()
+		return
+	}
+
+	ctx := &u.unfoldCtx
+	ctx.initPath(to)
+	ctx.unknownFields = nil
+	u.init(ctx, to)
+}
+
+// Unfold unfolds the document emitted by src into to. The unfolder is reset
+// if unfolding fails, so it can be reused for the next document.
+func (u *TypedUnfolder[T]) Unfold(to *T, src func(structform.Visitor) error) error {
+	if to == nil {
+		return errNilInput
+	}
+
+	u.SetTarget(to)
+	err := src(u)
+	if err == nil && len(u.unfolder.stack) > 0 {
+		err = errIncompleteDocument
+	}
+	if err != nil {
+		u.Reset()
+	}
+	return err
+}
+
+// NewTypedIterator creates an Iterator folding values of type T into vs.
+func NewTypedIterator[T any](vs structform.Visitor, opts ...FoldOption) (*TypedIterator[T], error) {
+	it, err := NewIterator(vs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	fold, err := getReflectFold(&it.ctx, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &TypedIterator[T]{ctx: it.ctx, fold: fold}, nil
+}
+
+// Fold folds v into the visitor.
+func (it *TypedIterator[T]) Fold(v T) error {
+	return it.fold(&it.ctx, reflect.ValueOf(&v).Elem())
+}
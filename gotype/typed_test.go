@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+)
+
+func parseJSON(in string) func(structform.Visitor) error {
+	return func(vs structform.Visitor) error {
+		return json.ParseString(in, vs)
+	}
+}
+
+func TestUnfoldAs(t *testing.T) {
+	type config struct {
+		Name  string
+		Hosts []string
+		Port  *int
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		port := 9200
+		cfg, err := UnfoldAs[config](parseJSON(`{"name": "test", "hosts": ["a", "b"], "port": 9200}`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, config{Name: "test", Hosts: []string{"a", "b"}, Port: &port}, cfg)
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		cfg, err := UnfoldAs[*config](parseJSON(`{"name": "test"}`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, &config{Name: "test"}, cfg)
+		}
+	})
+
+	t.Run("primitive", func(t *testing.T) {
+		i, err := UnfoldAs[int](parseJSON(`42`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, 42, i)
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		m, err := UnfoldAs[map[string]interface{}](parseJSON(`{"a": [1, "b"]}`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]interface{}{"a": []interface{}{int64(1), "b"}}, m)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		_, err := UnfoldAs[config](parseJSON(`{"unknown": 1}`), UnfoldUnknownFields(UnknownFieldFail))
+		assert.Error(t, err)
+	})
+
+	t.Run("incomplete document", func(t *testing.T) {
+		_, err := UnfoldAs[config](func(vs structform.Visitor) error {
+			if err := vs.OnObjectStart(-1, structform.AnyType); err != nil {
+				return err
+			}
+			if err := vs.OnKey("name"); err != nil {
+				return err
+			}
+			return vs.OnString("test")
+		})
+		assert.Equal(t, errIncompleteDocument, err)
+	})
+}
+
+func TestTypedUnfolderReuse(t *testing.T) {
+	type point struct{ X, Y int }
+
+	u, err := NewTypedUnfolder[point]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p point
+	assert.NoError(t, u.Unfold(&p, parseJSON(`{"x": 1, "y": 2}`)))
+	assert.Equal(t, point{1, 2}, p)
+
+	// failing document must not affect the next one
+	assert.Error(t, u.Unfold(&p, parseJSON(`{"x": "a"}`)))
+
+	p = point{}
+	assert.NoError(t, u.Unfold(&p, parseJSON(`{"x": 3, "y": 4}`)))
+	assert.Equal(t, point{3, 4}, p)
+
+	assert.Equal(t, errNilInput, u.Unfold(nil, parseJSON(`{}`)))
+}
+
+func TestTypedIterator(t *testing.T) {
+	type point struct {
+		FieldX int
+		FieldY int
+	}
+
+	buf := bytes.NewBuffer(nil)
+	it, err := NewTypedIterator[point](json.NewVisitor(buf), FoldNaming(NameSnakeCase))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, it.Fold(point{1, 2}))
+	assertJSON(t, `{"field_x": 1, "field_y": 2}`, buf.String())
+
+	buf.Reset()
+	ifc, err := NewTypedIterator[interface{}](json.NewVisitor(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, ifc.Fold(map[string]interface{}{"a": []int{1}}))
+	assertJSON(t, `{"a": [1]}`, buf.String())
+}
@@ -43,8 +43,23 @@ type unfoldCtx struct {
 	key      keyStack
 	idx      idxStack
 
+	// seen records the required and defaulted fields present in the
+	// structs being unfolded
+	seen fieldSetStack
+
+	// document path and target type for error reporting
+	path    pathStack
+	pathBuf []byte
+	target  reflect.Type
+
 	keyCache symbolCache
 
+	// shared state for capturing json.Unmarshaler input
+	jsonCapture *unfolderJSONCapture
+
+	// paths of unknown struct fields, if UnknownFieldCollect is configured
+	unknownFields []string
+
 	valueBuffer unfoldBuf
 }
 
@@ -106,19 +121,33 @@ func NewUnfolder(to interface{}, opts ...UnfoldOption) (*Unfolder, error) {
 	}
 
 	u := &Unfolder{}
-	u.opts = options{tag: "struct"}
+	u.opts = options{
+		tags:          O.tags,
+		naming:        O.naming,
+		textMarshaler: !O.ignoreTextUnmarshaler,
+		jsonMarshaler: O.jsonUnmarshaler,
+
+		caseInsensitive: O.caseInsensitive,
+		unknownFields:   O.unknownFields,
+		numberChecks:    !O.ignoreNumberChecks,
+		coerce:          O.coerce,
+	}
 
 	u.unfolder.init(&unfolderNoTarget{})
 	u.value.init(reflect.Value{})
 	u.ptr.init()
 	u.key.init()
 	u.idx.init()
+	u.seen.init()
 	u.baseType.init()
 	u.valueBuffer.init()
+	u.initPath(nil)
 
 	u.reg = newTypeUnfoldRegistry()
+	u.userReg = map[reflect.Type]reflUnfolder{}
+	u.setDefaultUnfolder(tTime, liftGoUnfolder(newUnfolderTime(O.timeEnc)))
+	u.setDefaultUnfolder(tDuration, liftGoUnfolder(_singletonUnfolderDuration))
 	if O.unfoldFns != nil {
-		u.userReg = map[reflect.Type]reflUnfolder{}
 		for typ, unfolder := range O.unfoldFns {
 			u.userReg[typ] = unfolder
 			u.userReg[typ.Elem()] = unfolder // add non-pointer value for arrays/maps and other structs
@@ -138,6 +167,13 @@ func NewUnfolder(to interface{}, opts ...UnfoldOption) (*Unfolder, error) {
 	return u, nil
 }
 
+// setDefaultUnfolder registers a builtin unfolder for the type t and *t.
+// User provided unfolders take precedence.
+func (u *Unfolder) setDefaultUnfolder(t reflect.Type, unfolder reflUnfolder) {
+	u.userReg[t] = unfolder
+	u.userReg[reflect.PtrTo(t)] = unfolder
+}
+
 func (u *Unfolder) EnableKeyCache(max int) {
 	u.keyCache.init(max)
 }
@@ -153,6 +189,8 @@ func (u *Unfolder) Reset() {
 
 func (u *Unfolder) SetTarget(to interface{}) error {
 	ctx := &u.unfoldCtx
+	ctx.initPath(to)
+	ctx.unknownFields = nil
 
 	if to == nil {
 		// reset internal states on nil
@@ -161,6 +199,7 @@ func (u *Unfolder) SetTarget(to interface{}) error {
 		u.ptr.init()
 		u.key.init()
 		u.idx.init()
+		u.seen.init()
 		u.baseType.init()
 		u.valueBuffer.reset()
 
@@ -190,117 +229,189 @@ func (u *Unfolder) SetTarget(to interface{}) error {
 }
 
 func (u *unfoldCtx) OnObjectStart(len int, baseType structform.BaseType) error {
-	return u.unfolder.current.OnObjectStart(u, len, baseType)
+	if err := u.unfolder.current.OnObjectStart(u, len, baseType); err != nil {
+		return u.unfoldError(err, "OnObjectStart", baseType)
+	}
+	u.enterPath(false)
+	return nil
 }
 
 func (u *unfoldCtx) OnObjectFinished() error {
+	if err := u.onObjectFinished(); err != nil {
+		return u.unfoldError(err, "OnObjectFinished", structform.AnyType)
+	}
+	u.nextPathValue()
+	return u.checkDocumentEnd()
+}
+
+func (u *unfoldCtx) onObjectFinished() error {
 	lBefore := len(u.unfolder.stack) + 1
 
 	if err := u.unfolder.current.OnObjectFinished(u); err != nil {
 		return err
 	}
+	u.leavePath()
 
+	// Notify the parent unfolder. Unfolders finishing a value on child done
+	// (e.g. interface{}) remove themselves from the stack, in which case the
+	// next parent is notified as well.
 	lAfter := len(u.unfolder.stack) + 1
-	if old := u.unfolder.current; lAfter > 1 && lBefore > lAfter {
-		return old.OnChildObjectDone(u)
+	for lAfter > 1 && lBefore > lAfter {
+		if err := u.unfolder.current.OnChildObjectDone(u); err != nil {
+			return err
+		}
+
+		lBefore, lAfter = lAfter, len(u.unfolder.stack)+1
 	}
 
 	return nil
 }
 
 func (u *unfoldCtx) OnKey(s string) error {
-	return u.unfolder.current.OnKey(u, s)
+	u.setPathKey(s)
+	if err := u.unfolder.current.OnKey(u, s); err != nil {
+		return u.unfoldError(err, "OnKey", structform.StringType)
+	}
+	return nil
 }
 
 func (u *unfoldCtx) OnKeyRef(s []byte) error {
-	return u.unfolder.current.OnKeyRef(u, s)
+	// s references the parsers buffer. Unfolders set a copy of the key if
+	// required.
+	u.setPathKey("")
+	if err := u.unfolder.current.OnKeyRef(u, s); err != nil {
+		u.setPathKey(string(s))
+		return u.unfoldError(err, "OnKey", structform.StringType)
+	}
+	return nil
 }
 
 func (u *unfoldCtx) OnArrayStart(len int, baseType structform.BaseType) error {
-	return u.unfolder.current.OnArrayStart(u, len, baseType)
+	if err := u.unfolder.current.OnArrayStart(u, len, baseType); err != nil {
+		return u.unfoldError(err, "OnArrayStart", baseType)
+	}
+	u.enterPath(true)
+	return nil
 }
 
 func (u *unfoldCtx) OnArrayFinished() error {
+	if err := u.onArrayFinished(); err != nil {
+		return u.unfoldError(err, "OnArrayFinished", structform.AnyType)
+	}
+	u.nextPathValue()
+	return u.checkDocumentEnd()
+}
+
+func (u *unfoldCtx) onArrayFinished() error {
 	lBefore := len(u.unfolder.stack) + 1
 
 	if err := u.unfolder.current.OnArrayFinished(u); err != nil {
 		return err
 	}
+	u.leavePath()
 
+	// Notify the parent unfolder. Unfolders finishing a value on child done
+	// (e.g. interface{}) remove themselves from the stack, in which case the
+	// next parent is notified as well.
 	lAfter := len(u.unfolder.stack) + 1
-	if old := u.unfolder.current; lAfter > 1 && lBefore > lAfter {
-		return old.OnChildArrayDone(u)
+	for lAfter > 1 && lBefore > lAfter {
+		if err := u.unfolder.current.OnChildArrayDone(u); err != nil {
+			return err
+		}
+
+		lBefore, lAfter = lAfter, len(u.unfolder.stack)+1
 	}
 
 	return nil
 }
 
+// checkDocumentEnd reports the collected unknown fields once the top-level
+// object or array is finished.
+func (u *unfoldCtx) checkDocumentEnd() error {
+	if len(u.path.stack) > 0 || len(u.unknownFields) == 0 {
+		return nil
+	}
+
+	err := &UnknownFieldsError{Paths: u.unknownFields}
+	u.unknownFields = nil
+	return err
+}
+
+// onValue finishes a primitive value event.
+func (u *unfoldCtx) onValue(err error, event string, bt structform.BaseType) error {
+	if err != nil {
+		return u.unfoldError(err, event, bt)
+	}
+	u.nextPathValue()
+	return nil
+}
+
 func (u *unfoldCtx) OnNil() error {
-	return u.unfolder.current.OnNil(u)
+	return u.onValue(u.unfolder.current.OnNil(u), "OnNil", structform.AnyType)
 }
 
 func (u *unfoldCtx) OnBool(b bool) error {
-	return u.unfolder.current.OnBool(u, b)
+	return u.onValue(u.unfolder.current.OnBool(u, b), "OnBool", structform.BoolType)
 }
 
 func (u *unfoldCtx) OnString(s string) error {
-	return u.unfolder.current.OnString(u, s)
+	return u.onValue(u.unfolder.current.OnString(u, s), "OnString", structform.StringType)
 }
 
 func (u *unfoldCtx) OnStringRef(s []byte) error {
-	return u.unfolder.current.OnStringRef(u, s)
+	return u.onValue(u.unfolder.current.OnStringRef(u, s), "OnString", structform.StringType)
 }
 
 func (u *unfoldCtx) OnInt8(i int8) error {
-	return u.unfolder.current.OnInt8(u, i)
+	return u.onValue(u.unfolder.current.OnInt8(u, i), "OnInt8", structform.Int8Type)
 }
 
 func (u *unfoldCtx) OnInt16(i int16) error {
-	return u.unfolder.current.OnInt16(u, i)
+	return u.onValue(u.unfolder.current.OnInt16(u, i), "OnInt16", structform.Int16Type)
 }
 
 func (u *unfoldCtx) OnInt32(i int32) error {
-	return u.unfolder.current.OnInt32(u, i)
+	return u.onValue(u.unfolder.current.OnInt32(u, i), "OnInt32", structform.Int32Type)
 }
 
 func (u *unfoldCtx) OnInt64(i int64) error {
-	return u.unfolder.current.OnInt64(u, i)
+	return u.onValue(u.unfolder.current.OnInt64(u, i), "OnInt64", structform.Int64Type)
 }
 
 func (u *unfoldCtx) OnInt(i int) error {
-	return u.unfolder.current.OnInt(u, i)
+	return u.onValue(u.unfolder.current.OnInt(u, i), "OnInt", structform.IntType)
 }
 
 func (u *unfoldCtx) OnByte(b byte) error {
-	return u.unfolder.current.OnByte(u, b)
+	return u.onValue(u.unfolder.current.OnByte(u, b), "OnByte", structform.ByteType)
 }
 
 func (u *unfoldCtx) OnUint8(v uint8) error {
-	return u.unfolder.current.OnUint8(u, v)
+	return u.onValue(u.unfolder.current.OnUint8(u, v), "OnUint8", structform.Uint8Type)
 }
 
 func (u *unfoldCtx) OnUint16(v uint16) error {
-	return u.unfolder.current.OnUint16(u, v)
+	return u.onValue(u.unfolder.current.OnUint16(u, v), "OnUint16", structform.Uint16Type)
 }
 
 func (u *unfoldCtx) OnUint32(v uint32) error {
-	return u.unfolder.current.OnUint32(u, v)
+	return u.onValue(u.unfolder.current.OnUint32(u, v), "OnUint32", structform.Uint32Type)
 }
 
 func (u *unfoldCtx) OnUint64(v uint64) error {
-	return u.unfolder.current.OnUint64(u, v)
+	return u.onValue(u.unfolder.current.OnUint64(u, v), "OnUint64", structform.Uint64Type)
 }
 
 func (u *unfoldCtx) OnUint(v uint) error {
-	return u.unfolder.current.OnUint(u, v)
+	return u.onValue(u.unfolder.current.OnUint(u, v), "OnUint", structform.UintType)
 }
 
 func (u *unfoldCtx) OnFloat32(f float32) error {
-	return u.unfolder.current.OnFloat32(u, f)
+	return u.onValue(u.unfolder.current.OnFloat32(u, f), "OnFloat32", structform.Float32Type)
 }
 
 func (u *unfoldCtx) OnFloat64(f float64) error {
-	return u.unfolder.current.OnFloat64(u, f)
+	return u.onValue(u.unfolder.current.OnFloat64(u, f), "OnFloat64", structform.Float64Type)
 }
 
 func newTypeUnfoldRegistry() *typeUnfoldRegistry {
@@ -19,6 +19,8 @@
 package gotype
 
 import (
+	"math/bits"
+	"strconv"
 	"unsafe"
 
 	structform "github.com/elastic/go-structform"
@@ -79,13 +81,11 @@ func newUnfoldArrStartIfc() *unfoldArrStartIfc {
 func (u *unfolderArrIfc) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartIfc())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrIfc) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -130,8 +130,6 @@ func (u *unfolderArrIfc) append(ctx *unfoldCtx, v interface{}) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -158,13 +156,11 @@ func newUnfoldArrStartBool() *unfoldArrStartBool {
 func (u *unfolderArrBool) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartBool())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrBool) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -209,8 +205,6 @@ func (u *unfolderArrBool) append(ctx *unfoldCtx, v bool) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -237,13 +231,11 @@ func newUnfoldArrStartString() *unfoldArrStartString {
 func (u *unfolderArrString) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartString())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrString) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -288,8 +280,6 @@ func (u *unfolderArrString) append(ctx *unfoldCtx, v string) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -316,13 +306,11 @@ func newUnfoldArrStartUint() *unfoldArrStartUint {
 func (u *unfolderArrUint) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartUint())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrUint) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -367,8 +355,6 @@ func (u *unfolderArrUint) append(ctx *unfoldCtx, v uint) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -395,13 +381,11 @@ func newUnfoldArrStartUint8() *unfoldArrStartUint8 {
 func (u *unfolderArrUint8) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartUint8())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrUint8) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -446,8 +430,6 @@ func (u *unfolderArrUint8) append(ctx *unfoldCtx, v uint8) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -474,13 +456,11 @@ func newUnfoldArrStartUint16() *unfoldArrStartUint16 {
 func (u *unfolderArrUint16) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartUint16())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrUint16) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -525,8 +505,6 @@ func (u *unfolderArrUint16) append(ctx *unfoldCtx, v uint16) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -553,13 +531,11 @@ func newUnfoldArrStartUint32() *unfoldArrStartUint32 {
 func (u *unfolderArrUint32) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartUint32())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrUint32) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -604,8 +580,6 @@ func (u *unfolderArrUint32) append(ctx *unfoldCtx, v uint32) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -632,13 +606,11 @@ func newUnfoldArrStartUint64() *unfoldArrStartUint64 {
 func (u *unfolderArrUint64) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartUint64())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrUint64) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -683,8 +655,6 @@ func (u *unfolderArrUint64) append(ctx *unfoldCtx, v uint64) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -711,13 +681,11 @@ func newUnfoldArrStartInt() *unfoldArrStartInt {
 func (u *unfolderArrInt) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartInt())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrInt) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -762,8 +730,6 @@ func (u *unfolderArrInt) append(ctx *unfoldCtx, v int) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -790,13 +756,11 @@ func newUnfoldArrStartInt8() *unfoldArrStartInt8 {
 func (u *unfolderArrInt8) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartInt8())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrInt8) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -841,8 +805,6 @@ func (u *unfolderArrInt8) append(ctx *unfoldCtx, v int8) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -869,13 +831,11 @@ func newUnfoldArrStartInt16() *unfoldArrStartInt16 {
 func (u *unfolderArrInt16) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartInt16())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrInt16) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -920,8 +880,6 @@ func (u *unfolderArrInt16) append(ctx *unfoldCtx, v int16) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -948,13 +906,11 @@ func newUnfoldArrStartInt32() *unfoldArrStartInt32 {
 func (u *unfolderArrInt32) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartInt32())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrInt32) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -999,8 +955,6 @@ func (u *unfolderArrInt32) append(ctx *unfoldCtx, v int32) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -1027,13 +981,11 @@ func newUnfoldArrStartInt64() *unfoldArrStartInt64 {
 func (u *unfolderArrInt64) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartInt64())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrInt64) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -1078,8 +1030,6 @@ func (u *unfolderArrInt64) append(ctx *unfoldCtx, v int64) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -1106,13 +1056,11 @@ func newUnfoldArrStartFloat32() *unfoldArrStartFloat32 {
 func (u *unfolderArrFloat32) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartFloat32())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrFloat32) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -1157,8 +1105,6 @@ func (u *unfolderArrFloat32) append(ctx *unfoldCtx, v float32) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -1185,13 +1131,11 @@ func newUnfoldArrStartFloat64() *unfoldArrStartFloat64 {
 func (u *unfolderArrFloat64) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(newUnfoldArrStartFloat64())
-	ctx.idx.push(0)
 	ctx.ptr.push(ptr)
 }
 
 func (u *unfolderArrFloat64) cleanup(ctx *unfoldCtx) {
 	ctx.unfolder.pop()
-	ctx.idx.pop()
 	ctx.ptr.pop()
 }
 
@@ -1236,8 +1180,6 @@ func (u *unfolderArrFloat64) append(ctx *unfoldCtx, v float64) error {
 	} else {
 		(*to)[idx.current] = v
 	}
-
-	idx.current++
 	return nil
 }
 
@@ -1334,6 +1276,13 @@ func (u *unfolderArrBool) OnNil(ctx *unfoldCtx) error {
 
 func (u *unfolderArrBool) OnBool(ctx *unfoldCtx, v bool) error { return u.append(ctx, v) }
 
+func (u *unfolderArrBool) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceBool(u, v)
+}
+func (u *unfolderArrBool) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceBool(u, bytes2Str(v))
+}
+
 func (u *unfolderArrString) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, "")
 }
@@ -1343,11 +1292,112 @@ func (u *unfolderArrString) OnStringRef(ctx *unfoldCtx, v []byte) error {
 	return u.OnString(ctx, string(v))
 }
 
+func (u *unfolderArrString) OnBool(ctx *unfoldCtx, v bool) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatBool(v))
+}
+
+func (u *unfolderArrString) OnByte(ctx *unfoldCtx, v byte) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnUint(ctx *unfoldCtx, v uint) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderArrString) OnInt(ctx *unfoldCtx, v int) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderArrString) OnInt8(ctx *unfoldCtx, v int8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderArrString) OnInt16(ctx *unfoldCtx, v int16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderArrString) OnInt32(ctx *unfoldCtx, v int32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderArrString) OnInt64(ctx *unfoldCtx, v int64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderArrString) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+func (u *unfolderArrString) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.append(ctx, strconv.FormatFloat(float64(v), 'g', -1, 64))
+}
+
 func (u *unfolderArrUint) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrUint) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
@@ -1356,49 +1406,89 @@ func (u *unfolderArrUint) OnUint(ctx *unfoldCtx, v uint) error {
 }
 
 func (u *unfolderArrUint) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
 func (u *unfolderArrUint) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, uint(v))
 }
 
+func (u *unfolderArrUint) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrUint) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrUint8) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
@@ -1408,6 +1498,9 @@ func (u *unfolderArrUint8) OnByte(ctx *unfoldCtx, v byte) error {
 }
 
 func (u *unfolderArrUint8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
@@ -1416,58 +1509,104 @@ func (u *unfolderArrUint8) OnUint8(ctx *unfoldCtx, v uint8) error {
 }
 
 func (u *unfolderArrUint8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
 func (u *unfolderArrUint8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, uint8(v))
 }
 
+func (u *unfolderArrUint8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrUint8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrUint16) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrUint16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
@@ -1476,58 +1615,104 @@ func (u *unfolderArrUint16) OnUint16(ctx *unfoldCtx, v uint16) error {
 }
 
 func (u *unfolderArrUint16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
 func (u *unfolderArrUint16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, uint16(v))
 }
 
+func (u *unfolderArrUint16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrUint16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrUint32) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrUint32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
@@ -1536,58 +1721,104 @@ func (u *unfolderArrUint32) OnUint32(ctx *unfoldCtx, v uint32) error {
 }
 
 func (u *unfolderArrUint32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
 func (u *unfolderArrUint32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, uint32(v))
 }
 
+func (u *unfolderArrUint32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrUint32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrUint64) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrUint64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
@@ -1596,58 +1827,104 @@ func (u *unfolderArrUint64) OnUint64(ctx *unfoldCtx, v uint64) error {
 }
 
 func (u *unfolderArrUint64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
 func (u *unfolderArrUint64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, uint64(v))
 }
 
+func (u *unfolderArrUint64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrUint64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrInt) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrInt) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
@@ -1656,58 +1933,104 @@ func (u *unfolderArrInt) OnInt(ctx *unfoldCtx, v int) error {
 }
 
 func (u *unfolderArrInt) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
 func (u *unfolderArrInt) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.append(ctx, int(v))
 }
 
+func (u *unfolderArrInt) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrInt) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrInt8) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrInt8) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
@@ -1716,58 +2039,104 @@ func (u *unfolderArrInt8) OnInt8(ctx *unfoldCtx, v int8) error {
 }
 
 func (u *unfolderArrInt8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
 func (u *unfolderArrInt8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.append(ctx, int8(v))
 }
 
+func (u *unfolderArrInt8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrInt8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrInt16) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrInt16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
@@ -1776,58 +2145,104 @@ func (u *unfolderArrInt16) OnInt16(ctx *unfoldCtx, v int16) error {
 }
 
 func (u *unfolderArrInt16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
 func (u *unfolderArrInt16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.append(ctx, int16(v))
 }
 
+func (u *unfolderArrInt16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrInt16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrInt32) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrInt32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
@@ -1836,58 +2251,104 @@ func (u *unfolderArrInt32) OnInt32(ctx *unfoldCtx, v int32) error {
 }
 
 func (u *unfolderArrInt32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
 func (u *unfolderArrInt32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, int32(v))
 }
 
+func (u *unfolderArrInt32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrInt32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrInt64) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrInt64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
@@ -1896,58 +2357,104 @@ func (u *unfolderArrInt64) OnInt64(ctx *unfoldCtx, v int64) error {
 }
 
 func (u *unfolderArrInt64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
 func (u *unfolderArrInt64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, int64(v))
 }
 
+func (u *unfolderArrInt64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrInt64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrFloat32) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrFloat32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
 func (u *unfolderArrFloat32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
@@ -1956,58 +2463,104 @@ func (u *unfolderArrFloat32) OnFloat32(ctx *unfoldCtx, v float32) error {
 }
 
 func (u *unfolderArrFloat32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.append(ctx, float32(v))
 }
 
+func (u *unfolderArrFloat32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrFloat32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderArrFloat64) OnNil(ctx *unfoldCtx) error {
 	return u.append(ctx, 0)
 }
 
 func (u *unfolderArrFloat64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
 func (u *unfolderArrFloat64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.append(ctx, float64(v))
 }
 
@@ -2015,6 +2568,13 @@ func (u *unfolderArrFloat64) OnFloat64(ctx *unfoldCtx, v float64) error {
 	return u.append(ctx, float64(v))
 }
 
+func (u *unfolderArrFloat64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderArrFloat64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func unfoldIfcStartSubArray(ctx *unfoldCtx, l int, baseType structform.BaseType) error {
 	_, ptr, unfolder := makeArrayPtr(ctx, l, baseType)
 	ctx.ptr.push(ptr) // store pointer for use in 'Finish'
@@ -45,22 +45,23 @@ func (*unfoldIgnorePtr) initState(ctx *unfoldCtx, _ unsafe.Pointer) {
 	ctx.unfolder.push(_singletonunfolderIgnore)
 }
 
-func (u *unfolderIgnore) OnNil(ctx *unfoldCtx) error                { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnBool(ctx *unfoldCtx, _ bool) error       { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnString(ctx *unfoldCtx, _ string) error   { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnInt8(ctx *unfoldCtx, _ int8) error       { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnInt16(ctx *unfoldCtx, _ int16) error     { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnInt32(ctx *unfoldCtx, _ int32) error     { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnInt64(ctx *unfoldCtx, _ int64) error     { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnInt(ctx *unfoldCtx, _ int) error         { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnByte(ctx *unfoldCtx, _ byte) error       { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnUint8(ctx *unfoldCtx, _ uint8) error     { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnUint16(ctx *unfoldCtx, _ uint16) error   { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnUint32(ctx *unfoldCtx, _ uint32) error   { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnUint64(ctx *unfoldCtx, _ uint64) error   { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnUint(ctx *unfoldCtx, _ uint) error       { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnFloat32(ctx *unfoldCtx, _ float32) error { return u.onValue(ctx) }
-func (u *unfolderIgnore) OnFloat64(ctx *unfoldCtx, _ float64) error { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnNil(ctx *unfoldCtx) error                 { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnBool(ctx *unfoldCtx, _ bool) error        { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnString(ctx *unfoldCtx, _ string) error    { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnStringRef(ctx *unfoldCtx, _ []byte) error { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnInt8(ctx *unfoldCtx, _ int8) error        { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnInt16(ctx *unfoldCtx, _ int16) error      { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnInt32(ctx *unfoldCtx, _ int32) error      { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnInt64(ctx *unfoldCtx, _ int64) error      { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnInt(ctx *unfoldCtx, _ int) error          { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnByte(ctx *unfoldCtx, _ byte) error        { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnUint8(ctx *unfoldCtx, _ uint8) error      { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnUint16(ctx *unfoldCtx, _ uint16) error    { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnUint32(ctx *unfoldCtx, _ uint32) error    { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnUint64(ctx *unfoldCtx, _ uint64) error    { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnUint(ctx *unfoldCtx, _ uint) error        { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnFloat32(ctx *unfoldCtx, _ float32) error  { return u.onValue(ctx) }
+func (u *unfolderIgnore) OnFloat64(ctx *unfoldCtx, _ float64) error  { return u.onValue(ctx) }
 
 func (u *unfolderIgnore) OnArrayStart(ctx *unfoldCtx, _ int, _ structform.BaseType) error {
 	_singletonUnfoldIgnoreArrPtr.initState(ctx, nil)
@@ -105,22 +106,23 @@ func (*unfoldIgnoreArrPtr) initState(ctx *unfoldCtx, _ unsafe.Pointer) {
 	ctx.unfolder.push(_singletonunfolderIgnoreArr)
 }
 
-func (u *unfolderIgnoreArr) OnNil(ctx *unfoldCtx) error                { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnBool(ctx *unfoldCtx, _ bool) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnString(ctx *unfoldCtx, _ string) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnInt8(ctx *unfoldCtx, _ int8) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnInt16(ctx *unfoldCtx, _ int16) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnInt32(ctx *unfoldCtx, _ int32) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnInt64(ctx *unfoldCtx, _ int64) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnInt(ctx *unfoldCtx, _ int) error         { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnByte(ctx *unfoldCtx, _ byte) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnUint8(ctx *unfoldCtx, _ uint8) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnUint16(ctx *unfoldCtx, _ uint16) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnUint32(ctx *unfoldCtx, _ uint32) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnUint64(ctx *unfoldCtx, _ uint64) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnUint(ctx *unfoldCtx, _ uint) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnFloat32(ctx *unfoldCtx, _ float32) error { return u.onValue(ctx) }
-func (u *unfolderIgnoreArr) OnFloat64(ctx *unfoldCtx, _ float64) error { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnNil(ctx *unfoldCtx) error                 { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnBool(ctx *unfoldCtx, _ bool) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnString(ctx *unfoldCtx, _ string) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnStringRef(ctx *unfoldCtx, _ []byte) error { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnInt8(ctx *unfoldCtx, _ int8) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnInt16(ctx *unfoldCtx, _ int16) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnInt32(ctx *unfoldCtx, _ int32) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnInt64(ctx *unfoldCtx, _ int64) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnInt(ctx *unfoldCtx, _ int) error          { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnByte(ctx *unfoldCtx, _ byte) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnUint8(ctx *unfoldCtx, _ uint8) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnUint16(ctx *unfoldCtx, _ uint16) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnUint32(ctx *unfoldCtx, _ uint32) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnUint64(ctx *unfoldCtx, _ uint64) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnUint(ctx *unfoldCtx, _ uint) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnFloat32(ctx *unfoldCtx, _ float32) error  { return u.onValue(ctx) }
+func (u *unfolderIgnoreArr) OnFloat64(ctx *unfoldCtx, _ float64) error  { return u.onValue(ctx) }
 
 func (u *unfolderIgnoreArr) OnArrayStart(ctx *unfoldCtx, _ int, _ structform.BaseType) error {
 	_singletonUnfoldIgnoreArrPtr.initState(ctx, nil)
@@ -169,22 +171,23 @@ func (*unfoldIgnoreObjPtr) initState(ctx *unfoldCtx, _ unsafe.Pointer) {
 	ctx.unfolder.push(_singletonunfolderIgnoreObj)
 }
 
-func (u *unfolderIgnoreObj) OnNil(ctx *unfoldCtx) error                { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnBool(ctx *unfoldCtx, _ bool) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnString(ctx *unfoldCtx, _ string) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnInt8(ctx *unfoldCtx, _ int8) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnInt16(ctx *unfoldCtx, _ int16) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnInt32(ctx *unfoldCtx, _ int32) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnInt64(ctx *unfoldCtx, _ int64) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnInt(ctx *unfoldCtx, _ int) error         { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnByte(ctx *unfoldCtx, _ byte) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnUint8(ctx *unfoldCtx, _ uint8) error     { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnUint16(ctx *unfoldCtx, _ uint16) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnUint32(ctx *unfoldCtx, _ uint32) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnUint64(ctx *unfoldCtx, _ uint64) error   { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnUint(ctx *unfoldCtx, _ uint) error       { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnFloat32(ctx *unfoldCtx, _ float32) error { return u.onValue(ctx) }
-func (u *unfolderIgnoreObj) OnFloat64(ctx *unfoldCtx, _ float64) error { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnNil(ctx *unfoldCtx) error                 { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnBool(ctx *unfoldCtx, _ bool) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnString(ctx *unfoldCtx, _ string) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnStringRef(ctx *unfoldCtx, _ []byte) error { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnInt8(ctx *unfoldCtx, _ int8) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnInt16(ctx *unfoldCtx, _ int16) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnInt32(ctx *unfoldCtx, _ int32) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnInt64(ctx *unfoldCtx, _ int64) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnInt(ctx *unfoldCtx, _ int) error          { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnByte(ctx *unfoldCtx, _ byte) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnUint8(ctx *unfoldCtx, _ uint8) error      { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnUint16(ctx *unfoldCtx, _ uint16) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnUint32(ctx *unfoldCtx, _ uint32) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnUint64(ctx *unfoldCtx, _ uint64) error    { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnUint(ctx *unfoldCtx, _ uint) error        { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnFloat32(ctx *unfoldCtx, _ float32) error  { return u.onValue(ctx) }
+func (u *unfolderIgnoreObj) OnFloat64(ctx *unfoldCtx, _ float64) error  { return u.onValue(ctx) }
 
 func (u *unfolderIgnoreObj) OnArrayStart(ctx *unfoldCtx, _ int, _ structform.BaseType) error {
 	_singletonUnfoldIgnoreArrPtr.initState(ctx, nil)
@@ -212,3 +215,6 @@ func (*unfolderIgnoreObj) OnObjectFinished(ctx *unfoldCtx) error {
 	ctx.unfolder.pop()
 	return nil
 }
+
+func (*unfolderIgnoreObj) OnKey(_ *unfoldCtx, _ string) error    { return nil }
+func (*unfolderIgnoreObj) OnKeyRef(_ *unfoldCtx, _ []byte) error { return nil }
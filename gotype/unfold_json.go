@@ -0,0 +1,206 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"reflect"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+)
+
+// unfolderReflJSON unfolds into types implementing json.Unmarshaler. The
+// sub-document is captured as JSON, which is passed to UnmarshalJSON once the
+// value is complete.
+type unfolderReflJSON struct{}
+
+// unfolderJSONCapture encodes all events into buf until the current value is
+// complete. Captures can not be nested, such that one instance is shared
+// per unfoldCtx.
+type unfolderJSONCapture struct {
+	buf   bytes.Buffer
+	vs    *json.Visitor
+	depth int
+}
+
+var _singletonUnfolderReflJSON = &unfolderReflJSON{}
+
+// lookupReflUnmarshaler returns an unfolder for the pointer type t, if t
+// implements json.Unmarshaler or encoding.TextUnmarshaler and support for the
+// interface is enabled. json.Unmarshaler takes precedence.
+func lookupReflUnmarshaler(ctx *unfoldCtx, t reflect.Type) reflUnfolder {
+	switch {
+	case ctx.opts.jsonMarshaler && t.Implements(tJSONUnmarshaler):
+		return _singletonUnfolderReflJSON
+	case isTextUnmarshaler(ctx, t):
+		return _singletonUnfolderReflText
+	}
+	return nil
+}
+
+// hasUnmarshalerElem checks if t is a slice or map type with elements
+// implementing json.Unmarshaler or encoding.TextUnmarshaler.
+func hasUnmarshalerElem(ctx *unfoldCtx, t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		return lookupReflUnmarshaler(ctx, reflect.PtrTo(t.Elem())) != nil
+	}
+	return false
+}
+
+func (u *unfolderReflJSON) initState(ctx *unfoldCtx, v reflect.Value) {
+	if ctx.jsonCapture == nil {
+		ctx.jsonCapture = newUnfolderJSONCapture()
+	}
+
+	c := ctx.jsonCapture
+	c.reset()
+	ctx.value.push(v)
+	ctx.unfolder.push(c)
+}
+
+func newUnfolderJSONCapture() *unfolderJSONCapture {
+	u := &unfolderJSONCapture{}
+	u.vs = json.NewVisitor(&u.buf)
+	return u
+}
+
+func (u *unfolderJSONCapture) reset() {
+	if u.depth != 0 {
+		// previous capture did fail. Reset visitor state.
+		u.vs = json.NewVisitor(&u.buf)
+		u.depth = 0
+	}
+	u.buf.Reset()
+}
+
+// done calls UnmarshalJSON if the captured value is complete.
+func (u *unfolderJSONCapture) done(ctx *unfoldCtx, err error) error {
+	if err != nil || u.depth > 0 {
+		return err
+	}
+
+	v := ctx.value.pop()
+	ctx.unfolder.pop()
+
+	err = v.Interface().(gojson.Unmarshaler).UnmarshalJSON(u.buf.Bytes())
+	u.buf.Reset()
+	return err
+}
+
+func (u *unfolderJSONCapture) OnNil(ctx *unfoldCtx) error {
+	return u.done(ctx, u.vs.OnNil())
+}
+
+func (u *unfolderJSONCapture) OnBool(ctx *unfoldCtx, v bool) error {
+	return u.done(ctx, u.vs.OnBool(v))
+}
+
+func (u *unfolderJSONCapture) OnString(ctx *unfoldCtx, v string) error {
+	return u.done(ctx, u.vs.OnString(v))
+}
+
+func (u *unfolderJSONCapture) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return u.done(ctx, u.vs.OnStringRef(v))
+}
+
+func (u *unfolderJSONCapture) OnInt8(ctx *unfoldCtx, v int8) error {
+	return u.done(ctx, u.vs.OnInt8(v))
+}
+
+func (u *unfolderJSONCapture) OnInt16(ctx *unfoldCtx, v int16) error {
+	return u.done(ctx, u.vs.OnInt16(v))
+}
+
+func (u *unfolderJSONCapture) OnInt32(ctx *unfoldCtx, v int32) error {
+	return u.done(ctx, u.vs.OnInt32(v))
+}
+
+func (u *unfolderJSONCapture) OnInt64(ctx *unfoldCtx, v int64) error {
+	return u.done(ctx, u.vs.OnInt64(v))
+}
+
+func (u *unfolderJSONCapture) OnInt(ctx *unfoldCtx, v int) error {
+	return u.done(ctx, u.vs.OnInt(v))
+}
+
+func (u *unfolderJSONCapture) OnByte(ctx *unfoldCtx, v byte) error {
+	return u.done(ctx, u.vs.OnByte(v))
+}
+
+func (u *unfolderJSONCapture) OnUint8(ctx *unfoldCtx, v uint8) error {
+	return u.done(ctx, u.vs.OnUint8(v))
+}
+
+func (u *unfolderJSONCapture) OnUint16(ctx *unfoldCtx, v uint16) error {
+	return u.done(ctx, u.vs.OnUint16(v))
+}
+
+func (u *unfolderJSONCapture) OnUint32(ctx *unfoldCtx, v uint32) error {
+	return u.done(ctx, u.vs.OnUint32(v))
+}
+
+func (u *unfolderJSONCapture) OnUint64(ctx *unfoldCtx, v uint64) error {
+	return u.done(ctx, u.vs.OnUint64(v))
+}
+
+func (u *unfolderJSONCapture) OnUint(ctx *unfoldCtx, v uint) error {
+	return u.done(ctx, u.vs.OnUint(v))
+}
+
+func (u *unfolderJSONCapture) OnFloat32(ctx *unfoldCtx, v float32) error {
+	return u.done(ctx, u.vs.OnFloat32(v))
+}
+
+func (u *unfolderJSONCapture) OnFloat64(ctx *unfoldCtx, v float64) error {
+	return u.done(ctx, u.vs.OnFloat64(v))
+}
+
+func (u *unfolderJSONCapture) OnArrayStart(ctx *unfoldCtx, l int, bt structform.BaseType) error {
+	u.depth++
+	return u.vs.OnArrayStart(l, bt)
+}
+
+func (u *unfolderJSONCapture) OnArrayFinished(ctx *unfoldCtx) error {
+	u.depth--
+	return u.done(ctx, u.vs.OnArrayFinished())
+}
+
+func (u *unfolderJSONCapture) OnObjectStart(ctx *unfoldCtx, l int, bt structform.BaseType) error {
+	u.depth++
+	return u.vs.OnObjectStart(l, bt)
+}
+
+func (u *unfolderJSONCapture) OnObjectFinished(ctx *unfoldCtx) error {
+	u.depth--
+	return u.done(ctx, u.vs.OnObjectFinished())
+}
+
+func (u *unfolderJSONCapture) OnKey(ctx *unfoldCtx, k string) error {
+	return u.vs.OnKey(k)
+}
+
+func (u *unfolderJSONCapture) OnKeyRef(ctx *unfoldCtx, k []byte) error {
+	return u.vs.OnKeyRef(k)
+}
+
+// the capture does not push child unfolders
+func (u *unfolderJSONCapture) OnChildArrayDone(ctx *unfoldCtx) error  { return errInvalidState }
+func (u *unfolderJSONCapture) OnChildObjectDone(ctx *unfoldCtx) error { return errInvalidState }
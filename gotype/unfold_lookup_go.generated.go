@@ -351,6 +351,10 @@ func lookupReflUnfolder(ctx *unfoldCtx, t reflect.Type, withUser bool) (reflUnfo
 		return newExpanderInit(), nil
 	}
 
+	if f := lookupReflUnmarshaler(ctx, t); f != nil {
+		return f, nil
+	}
+
 	if f := ctx.reg.find(t); f != nil {
 		return f, nil
 	}
@@ -442,6 +446,10 @@ func buildReflUnfolder(ctx *unfoldCtx, t reflect.Type) (reflUnfolder, error) {
 			return newUnfolderReflSlice(newExpanderInit()), nil
 		}
 
+		if unfolderElem := lookupReflUnmarshaler(ctx, reflect.PtrTo(et)); unfolderElem != nil {
+			return newUnfolderReflSlice(unfolderElem), nil
+		}
+
 		switch et.Kind() {
 		case reflect.Interface:
 			return unfolderReflArrIfc, nil
@@ -497,6 +505,10 @@ func buildReflUnfolder(ctx *unfoldCtx, t reflect.Type) (reflUnfolder, error) {
 		return newUnfolderReflSlice(unfolderElem), nil
 
 	case reflect.Map:
+		if bt.Key().Kind() != reflect.String {
+			return buildReflMapTextKeys(ctx, bt)
+		}
+
 		et := bt.Elem()
 
 		if unfolderElem := lookupReflUser(ctx, et); unfolderElem != nil {
@@ -507,6 +519,10 @@ func buildReflUnfolder(ctx *unfoldCtx, t reflect.Type) (reflUnfolder, error) {
 			return newUnfolderReflMap(newExpanderInit()), nil
 		}
 
+		if unfolderElem := lookupReflUnmarshaler(ctx, reflect.PtrTo(et)); unfolderElem != nil {
+			return newUnfolderReflMap(unfolderElem), nil
+		}
+
 		switch et.Kind() {
 		case reflect.Interface:
 			return unfolderReflMapIfc, nil
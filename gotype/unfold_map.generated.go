@@ -19,6 +19,8 @@
 package gotype
 
 import (
+	"math/bits"
+	"strconv"
 	"unsafe"
 
 	structform "github.com/elastic/go-structform"
@@ -117,7 +119,7 @@ func (u *unfoldMapKeyIfc) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyIfc) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapIfc()
 	return nil
 }
@@ -132,7 +134,7 @@ func (u *unfolderMapIfc) put(ctx *unfoldCtx, v interface{}) error {
 	if *to == nil {
 		*to = map[string]interface{}{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyIfc()
 	return nil
@@ -199,7 +201,7 @@ func (u *unfoldMapKeyBool) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyBool) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapBool()
 	return nil
 }
@@ -214,7 +216,7 @@ func (u *unfolderMapBool) put(ctx *unfoldCtx, v bool) error {
 	if *to == nil {
 		*to = map[string]bool{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyBool()
 	return nil
@@ -281,7 +283,7 @@ func (u *unfoldMapKeyString) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyString) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapString()
 	return nil
 }
@@ -296,7 +298,7 @@ func (u *unfolderMapString) put(ctx *unfoldCtx, v string) error {
 	if *to == nil {
 		*to = map[string]string{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyString()
 	return nil
@@ -363,7 +365,7 @@ func (u *unfoldMapKeyUint) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyUint) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapUint()
 	return nil
 }
@@ -378,7 +380,7 @@ func (u *unfolderMapUint) put(ctx *unfoldCtx, v uint) error {
 	if *to == nil {
 		*to = map[string]uint{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyUint()
 	return nil
@@ -445,7 +447,7 @@ func (u *unfoldMapKeyUint8) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyUint8) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapUint8()
 	return nil
 }
@@ -460,7 +462,7 @@ func (u *unfolderMapUint8) put(ctx *unfoldCtx, v uint8) error {
 	if *to == nil {
 		*to = map[string]uint8{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyUint8()
 	return nil
@@ -527,7 +529,7 @@ func (u *unfoldMapKeyUint16) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyUint16) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapUint16()
 	return nil
 }
@@ -542,7 +544,7 @@ func (u *unfolderMapUint16) put(ctx *unfoldCtx, v uint16) error {
 	if *to == nil {
 		*to = map[string]uint16{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyUint16()
 	return nil
@@ -609,7 +611,7 @@ func (u *unfoldMapKeyUint32) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyUint32) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapUint32()
 	return nil
 }
@@ -624,7 +626,7 @@ func (u *unfolderMapUint32) put(ctx *unfoldCtx, v uint32) error {
 	if *to == nil {
 		*to = map[string]uint32{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyUint32()
 	return nil
@@ -691,7 +693,7 @@ func (u *unfoldMapKeyUint64) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyUint64) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapUint64()
 	return nil
 }
@@ -706,7 +708,7 @@ func (u *unfolderMapUint64) put(ctx *unfoldCtx, v uint64) error {
 	if *to == nil {
 		*to = map[string]uint64{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyUint64()
 	return nil
@@ -773,7 +775,7 @@ func (u *unfoldMapKeyInt) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyInt) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapInt()
 	return nil
 }
@@ -788,7 +790,7 @@ func (u *unfolderMapInt) put(ctx *unfoldCtx, v int) error {
 	if *to == nil {
 		*to = map[string]int{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyInt()
 	return nil
@@ -855,7 +857,7 @@ func (u *unfoldMapKeyInt8) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyInt8) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapInt8()
 	return nil
 }
@@ -870,7 +872,7 @@ func (u *unfolderMapInt8) put(ctx *unfoldCtx, v int8) error {
 	if *to == nil {
 		*to = map[string]int8{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyInt8()
 	return nil
@@ -937,7 +939,7 @@ func (u *unfoldMapKeyInt16) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyInt16) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapInt16()
 	return nil
 }
@@ -952,7 +954,7 @@ func (u *unfolderMapInt16) put(ctx *unfoldCtx, v int16) error {
 	if *to == nil {
 		*to = map[string]int16{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyInt16()
 	return nil
@@ -1019,7 +1021,7 @@ func (u *unfoldMapKeyInt32) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyInt32) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapInt32()
 	return nil
 }
@@ -1034,7 +1036,7 @@ func (u *unfolderMapInt32) put(ctx *unfoldCtx, v int32) error {
 	if *to == nil {
 		*to = map[string]int32{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyInt32()
 	return nil
@@ -1101,7 +1103,7 @@ func (u *unfoldMapKeyInt64) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyInt64) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapInt64()
 	return nil
 }
@@ -1116,7 +1118,7 @@ func (u *unfolderMapInt64) put(ctx *unfoldCtx, v int64) error {
 	if *to == nil {
 		*to = map[string]int64{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyInt64()
 	return nil
@@ -1183,7 +1185,7 @@ func (u *unfoldMapKeyFloat32) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyFloat32) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapFloat32()
 	return nil
 }
@@ -1198,7 +1200,7 @@ func (u *unfolderMapFloat32) put(ctx *unfoldCtx, v float32) error {
 	if *to == nil {
 		*to = map[string]float32{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyFloat32()
 	return nil
@@ -1265,7 +1267,7 @@ func (u *unfoldMapKeyFloat64) OnKeyRef(ctx *unfoldCtx, key []byte) error {
 }
 
 func (u *unfoldMapKeyFloat64) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
 	ctx.unfolder.current = newUnfolderMapFloat64()
 	return nil
 }
@@ -1280,7 +1282,7 @@ func (u *unfolderMapFloat64) put(ctx *unfoldCtx, v float64) error {
 	if *to == nil {
 		*to = map[string]float64{}
 	}
-	(*to)[ctx.key.pop()] = v
+	(*to)[ctx.key.current] = v
 
 	ctx.unfolder.current = newUnfoldMapKeyFloat64()
 	return nil
@@ -1379,6 +1381,13 @@ func (u *unfolderMapBool) OnNil(ctx *unfoldCtx) error {
 
 func (u *unfolderMapBool) OnBool(ctx *unfoldCtx, v bool) error { return u.put(ctx, v) }
 
+func (u *unfolderMapBool) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceBool(u, v)
+}
+func (u *unfolderMapBool) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceBool(u, bytes2Str(v))
+}
+
 func (u *unfolderMapString) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, "")
 }
@@ -1388,11 +1397,112 @@ func (u *unfolderMapString) OnStringRef(ctx *unfoldCtx, v []byte) error {
 	return u.OnString(ctx, string(v))
 }
 
+func (u *unfolderMapString) OnBool(ctx *unfoldCtx, v bool) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatBool(v))
+}
+
+func (u *unfolderMapString) OnByte(ctx *unfoldCtx, v byte) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnUint(ctx *unfoldCtx, v uint) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderMapString) OnInt(ctx *unfoldCtx, v int) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderMapString) OnInt8(ctx *unfoldCtx, v int8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderMapString) OnInt16(ctx *unfoldCtx, v int16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderMapString) OnInt32(ctx *unfoldCtx, v int32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderMapString) OnInt64(ctx *unfoldCtx, v int64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderMapString) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+func (u *unfolderMapString) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.put(ctx, strconv.FormatFloat(float64(v), 'g', -1, 64))
+}
+
 func (u *unfolderMapUint) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapUint) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
@@ -1401,49 +1511,89 @@ func (u *unfolderMapUint) OnUint(ctx *unfoldCtx, v uint) error {
 }
 
 func (u *unfolderMapUint) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
 func (u *unfolderMapUint) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, uint(v))
 }
 
+func (u *unfolderMapUint) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapUint) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapUint8) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
@@ -1453,6 +1603,9 @@ func (u *unfolderMapUint8) OnByte(ctx *unfoldCtx, v byte) error {
 }
 
 func (u *unfolderMapUint8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
@@ -1461,58 +1614,104 @@ func (u *unfolderMapUint8) OnUint8(ctx *unfoldCtx, v uint8) error {
 }
 
 func (u *unfolderMapUint8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
 func (u *unfolderMapUint8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, uint8(v))
 }
 
+func (u *unfolderMapUint8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapUint8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapUint16) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapUint16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
@@ -1521,58 +1720,104 @@ func (u *unfolderMapUint16) OnUint16(ctx *unfoldCtx, v uint16) error {
 }
 
 func (u *unfolderMapUint16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
 func (u *unfolderMapUint16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, uint16(v))
 }
 
+func (u *unfolderMapUint16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapUint16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapUint32) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapUint32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
@@ -1581,58 +1826,104 @@ func (u *unfolderMapUint32) OnUint32(ctx *unfoldCtx, v uint32) error {
 }
 
 func (u *unfolderMapUint32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
 func (u *unfolderMapUint32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, uint32(v))
 }
 
+func (u *unfolderMapUint32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapUint32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapUint64) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapUint64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
@@ -1641,58 +1932,104 @@ func (u *unfolderMapUint64) OnUint64(ctx *unfoldCtx, v uint64) error {
 }
 
 func (u *unfolderMapUint64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
 func (u *unfolderMapUint64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, uint64(v))
 }
 
+func (u *unfolderMapUint64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapUint64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapInt) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapInt) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
@@ -1701,58 +2038,104 @@ func (u *unfolderMapInt) OnInt(ctx *unfoldCtx, v int) error {
 }
 
 func (u *unfolderMapInt) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
 func (u *unfolderMapInt) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.put(ctx, int(v))
 }
 
+func (u *unfolderMapInt) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapInt) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapInt8) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapInt8) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
@@ -1761,58 +2144,104 @@ func (u *unfolderMapInt8) OnInt8(ctx *unfoldCtx, v int8) error {
 }
 
 func (u *unfolderMapInt8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
 func (u *unfolderMapInt8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.put(ctx, int8(v))
 }
 
+func (u *unfolderMapInt8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapInt8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapInt16) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapInt16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
@@ -1821,58 +2250,104 @@ func (u *unfolderMapInt16) OnInt16(ctx *unfoldCtx, v int16) error {
 }
 
 func (u *unfolderMapInt16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
 func (u *unfolderMapInt16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.put(ctx, int16(v))
 }
 
+func (u *unfolderMapInt16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapInt16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapInt32) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapInt32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
@@ -1881,58 +2356,104 @@ func (u *unfolderMapInt32) OnInt32(ctx *unfoldCtx, v int32) error {
 }
 
 func (u *unfolderMapInt32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
 func (u *unfolderMapInt32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, int32(v))
 }
 
+func (u *unfolderMapInt32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapInt32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapInt64) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapInt64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
@@ -1941,58 +2462,104 @@ func (u *unfolderMapInt64) OnInt64(ctx *unfoldCtx, v int64) error {
 }
 
 func (u *unfolderMapInt64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
 func (u *unfolderMapInt64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, int64(v))
 }
 
+func (u *unfolderMapInt64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapInt64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapFloat32) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapFloat32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
 func (u *unfolderMapFloat32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
@@ -2001,58 +2568,104 @@ func (u *unfolderMapFloat32) OnFloat32(ctx *unfoldCtx, v float32) error {
 }
 
 func (u *unfolderMapFloat32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.put(ctx, float32(v))
 }
 
+func (u *unfolderMapFloat32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapFloat32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderMapFloat64) OnNil(ctx *unfoldCtx) error {
 	return u.put(ctx, 0)
 }
 
 func (u *unfolderMapFloat64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
 func (u *unfolderMapFloat64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.put(ctx, float64(v))
 }
 
@@ -2060,6 +2673,13 @@ func (u *unfolderMapFloat64) OnFloat64(ctx *unfoldCtx, v float64) error {
 	return u.put(ctx, float64(v))
 }
 
+func (u *unfolderMapFloat64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderMapFloat64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func unfoldIfcStartSubMap(ctx *unfoldCtx, l int, baseType structform.BaseType) error {
 	_, ptr, unfolder := makeMapPtr(ctx, l, baseType)
 	ctx.ptr.push(ptr)
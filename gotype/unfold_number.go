@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"fmt"
+	"math"
+)
+
+// Range and precision checks used by the generated primitive unfolders when
+// converting numbers. Values are passed in their widest type, with bits
+// being the size of the target type. The checks are no-ops if number checks
+// are disabled.
+
+func (c *unfoldCtx) checkIntFromInt(v int64, bits int) error {
+	if !c.opts.numberChecks || bits >= 64 {
+		return nil
+	}
+	if max := int64(1)<<uint(bits-1) - 1; v > max || v < -max-1 {
+		return errIntOverflow(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkIntFromUint(v uint64, bits int) error {
+	if c.opts.numberChecks && v > uint64(1)<<uint(bits-1)-1 {
+		return errIntOverflow(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkIntFromFloat(v float64, bits int) error {
+	if !c.opts.numberChecks {
+		return nil
+	}
+	if math.Trunc(v) != v {
+		return errNotInteger(v)
+	}
+	if limit := math.Ldexp(1, bits-1); v >= limit || v < -limit {
+		return errIntOverflow(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkUintFromInt(v int64, bits int) error {
+	if !c.opts.numberChecks {
+		return nil
+	}
+	if v < 0 {
+		return errUintOverflow(v, bits)
+	}
+	return c.checkUintFromUint(uint64(v), bits)
+}
+
+func (c *unfoldCtx) checkUintFromUint(v uint64, bits int) error {
+	if c.opts.numberChecks && bits < 64 && v > uint64(1)<<uint(bits)-1 {
+		return errUintOverflow(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkUintFromFloat(v float64, bits int) error {
+	if !c.opts.numberChecks {
+		return nil
+	}
+	if math.Trunc(v) != v {
+		return errNotInteger(v)
+	}
+	if v < 0 || v >= math.Ldexp(1, bits) {
+		return errUintOverflow(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkFloatFromInt(v int64, bits int) error {
+	if !c.opts.numberChecks {
+		return nil
+	}
+
+	// the value is exact if the conversion can be reverted
+	f := float64(v)
+	if bits == 32 {
+		f = float64(float32(v))
+	}
+	if f >= math.Ldexp(1, 63) || int64(f) != v {
+		return errFloatPrecision(v, bits)
+	}
+	return nil
+}
+
+func (c *unfoldCtx) checkFloatFromUint(v uint64, bits int) error {
+	if !c.opts.numberChecks {
+		return nil
+	}
+
+	f := float64(v)
+	if bits == 32 {
+		f = float64(float32(v))
+	}
+	if f >= math.Ldexp(1, 64) || uint64(f) != v {
+		return errFloatPrecision(v, bits)
+	}
+	return nil
+}
+
+// checkFloatFromFloat reports float64 values overflowing float32. Rounding to
+// the nearest float32 is not reported as error.
+func (c *unfoldCtx) checkFloatFromFloat(v float64, bits int) error {
+	if c.opts.numberChecks && bits == 32 && math.Abs(v) > math.MaxFloat32 && !math.IsInf(v, 0) {
+		return fmt.Errorf("number %v overflows float32", v)
+	}
+	return nil
+}
+
+func errIntOverflow(v interface{}, bits int) error {
+	return fmt.Errorf("number %v overflows %v bit signed integer", v, bits)
+}
+
+func errUintOverflow(v interface{}, bits int) error {
+	return fmt.Errorf("number %v overflows %v bit unsigned integer", v, bits)
+}
+
+func errNotInteger(v float64) error {
+	return fmt.Errorf("number %v is not an integer", v)
+}
+
+func errFloatPrecision(v interface{}, bits int) error {
+	return fmt.Errorf("number %v can not be represented as %v bit float without loss of precision", v, bits)
+}
@@ -23,8 +23,39 @@ import (
 
 type initUnfoldOptions struct {
 	unfoldFns map[reflect.Type]reflUnfolder
+
+	timeEnc TimeEncoding
+
+	ignoreTextUnmarshaler bool
+	jsonUnmarshaler       bool
+
+	tags   []string
+	naming NamingStrategy
+
+	caseInsensitive bool
+	unknownFields   UnknownFieldPolicy
+
+	ignoreNumberChecks bool
+	coerce             bool
 }
 
+// UnknownFieldPolicy configures the handling of object keys not matching any
+// struct field.
+type UnknownFieldPolicy uint8
+
+const (
+	// UnknownFieldIgnore skips values of unknown fields (default).
+	UnknownFieldIgnore UnknownFieldPolicy = iota
+
+	// UnknownFieldFail stops unfolding with an error at the first unknown
+	// field.
+	UnknownFieldFail
+
+	// UnknownFieldCollect skips unknown fields, but reports the paths of all
+	// unknown fields in an UnknownFieldsError once the document is complete.
+	UnknownFieldCollect
+)
+
 type UnfoldOption func(*initUnfoldOptions) error
 
 func applyUnfoldOpts(opts []UnfoldOption) (i initUnfoldOptions, err error) {
@@ -76,6 +107,100 @@ func Unfolders(in ...interface{}) UnfoldOption {
 	}
 }
 
+// UnfoldTime configures the encoding expected for time.Time values.
+// By default timestamps are parsed from RFC3339 strings. If an epoch based
+// encoding is configured, numeric values are accepted in addition to strings.
+// time.Duration values are unfolded from strings like "1m30s" or integer
+// nanoseconds.
+func UnfoldTime(enc TimeEncoding) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.timeEnc = enc
+		return nil
+	}
+}
+
+// UnfoldTextUnmarshaler configures support for types implementing
+// encoding.TextUnmarshaler. If enabled (default), strings are unfolded into
+// values and map keys implementing encoding.TextUnmarshaler.
+func UnfoldTextUnmarshaler(enabled bool) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.ignoreTextUnmarshaler = !enabled
+		return nil
+	}
+}
+
+// UnfoldJSONUnmarshaler configures support for types implementing
+// json.Unmarshaler. If enabled, the value is captured as JSON document, which
+// is passed to UnmarshalJSON. json.Unmarshaler takes precedence over
+// encoding.TextUnmarshaler. Support is disabled by default.
+func UnfoldJSONUnmarshaler(enabled bool) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.jsonUnmarshaler = enabled
+		return nil
+	}
+}
+
+// UnfoldTags configures the struct tags used to read field names and
+// options. If a field has multiple of the configured tags, the first tag in
+// the list is used. By default the 'struct' tag is used.
+func UnfoldTags(names ...string) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.tags = names
+		return nil
+	}
+}
+
+// UnfoldNaming configures the naming strategy for fields without name in the
+// struct tag. By default field names are converted to lower case.
+func UnfoldNaming(naming NamingStrategy) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.naming = naming
+		return nil
+	}
+}
+
+// UnfoldCaseInsensitive configures struct fields to be matched case
+// insensitive, if no field matches an object key exactly. Keys matching
+// multiple fields are reported as errors. Field name aliases can be configured
+// using the `alias=<name>` tag option.
+func UnfoldCaseInsensitive(enabled bool) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.caseInsensitive = enabled
+		return nil
+	}
+}
+
+// UnfoldUnknownFields configures the handling of object keys not matching
+// any struct field.
+func UnfoldUnknownFields(policy UnknownFieldPolicy) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.unknownFields = policy
+		return nil
+	}
+}
+
+// UnfoldNumberChecks configures range and precision checks when unfolding
+// numbers. If enabled (default), numbers not fitting into the target type
+// without overflow or truncation are reported as errors. Disabling the checks
+// converts numbers like Go type conversions do.
+func UnfoldNumberChecks(enabled bool) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.ignoreNumberChecks = !enabled
+		return nil
+	}
+}
+
+// UnfoldCoerce configures lenient conversion of primitive values. If enabled,
+// strings are parsed into numbers and booleans (e.g. "42" into an int), and
+// numbers and booleans are formatted into string targets. Coercion is
+// disabled by default.
+func UnfoldCoerce(enabled bool) UnfoldOption {
+	return func(o *initUnfoldOptions) error {
+		o.coerce = enabled
+		return nil
+	}
+}
+
 func makeUserUnfolderFns(in []interface{}) (map[reflect.Type]reflUnfolder, error) {
 	M := map[reflect.Type]reflUnfolder{}
 
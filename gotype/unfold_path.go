@@ -0,0 +1,208 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// pathElem records the kind of an active object or array. The current key of
+// each active object is stored in unfoldCtx.key, the current index of each
+// active array in unfoldCtx.idx. The document path is rebuilt from these
+// stacks if an error is reported.
+type pathElem struct {
+	array bool
+}
+
+func (u *unfoldCtx) initPath(to interface{}) {
+	u.path.init()
+	u.key.init()
+	u.idx.init()
+	if to != nil {
+		u.target = reflect.TypeOf(to)
+	} else {
+		u.target = nil
+	}
+}
+
+func (u *unfoldCtx) enterPath(array bool) {
+	u.path.push(pathElem{array: array})
+	if array {
+		u.idx.push(0)
+	} else {
+		u.key.push("")
+	}
+}
+
+func (u *unfoldCtx) leavePath() {
+	if len(u.path.stack) == 0 {
+		return
+	}
+	if u.path.pop().array {
+		u.idx.pop()
+	} else {
+		u.key.pop()
+	}
+}
+
+// setPathKey sets the key of the current object field. Unfolders must pass
+// keys not referencing the parsers input buffer.
+func (u *unfoldCtx) setPathKey(key string) {
+	if len(u.path.stack) > 0 && !u.path.current.array {
+		u.key.current = key
+	}
+}
+
+// nextPathValue advances the array index after a value has been unfolded.
+func (u *unfoldCtx) nextPathValue() {
+	if len(u.path.stack) > 0 && u.path.current.array {
+		u.idx.current++
+	}
+}
+
+// walkPath calls fn with the current key or index of each active object and
+// array, until fn returns false. The index is -1 for objects.
+func (u *unfoldCtx) walkPath(fn func(key string, idx int) bool) {
+	n := len(u.path.stack)
+	if n == 0 {
+		return
+	}
+
+	// skip the root elements of all stacks
+	keys, idxs := 1, 1
+	for i := 1; i <= n; i++ {
+		elem := u.path.current
+		if i < n {
+			elem = u.path.stack[i]
+		}
+
+		var cont bool
+		if elem.array {
+			cont = fn("", stackIdx(&u.idx, idxs))
+			idxs++
+		} else {
+			cont = fn(stackKey(&u.key, keys), -1)
+			keys++
+		}
+		if !cont {
+			return
+		}
+	}
+}
+
+func stackKey(s *keyStack, i int) string {
+	if i < len(s.stack) {
+		return s.stack[i]
+	}
+	return s.current
+}
+
+func stackIdx(s *idxStack, i int) int {
+	if i < len(s.stack) {
+		return s.stack[i]
+	}
+	return s.current
+}
+
+func (u *unfoldCtx) pathString() string {
+	var sb strings.Builder
+	u.walkPath(func(key string, idx int) bool {
+		if idx >= 0 {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(idx))
+			sb.WriteByte(']')
+			return true
+		}
+
+		if key == "" {
+			return false // no key read yet
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(key)
+		return true
+	})
+	return sb.String()
+}
+
+// expectedType derives the go type of the current value by following the
+// path from the target type. Returns nil if the type is not known.
+func (u *unfoldCtx) expectedType() reflect.Type {
+	t := u.target
+	if t == nil {
+		return nil
+	}
+
+	u.walkPath(func(key string, idx int) bool {
+		t = derefType(t)
+
+		if idx >= 0 {
+			if k := t.Kind(); k != reflect.Slice && k != reflect.Array {
+				t = nil
+				return false
+			}
+			t = t.Elem()
+			return true
+		}
+
+		if key == "" {
+			return false
+		}
+
+		switch t.Kind() {
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			t = fieldType(u, t, key)
+		default:
+			t = nil
+		}
+		return t != nil
+	})
+	if t == nil {
+		return nil
+	}
+
+	return derefType(t)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func (u *unfoldCtx) unfoldError(err error, event string, bt structform.BaseType) error {
+	if _, ok := err.(*UnfoldError); ok {
+		return err
+	}
+
+	return &UnfoldError{
+		Err:      err,
+		Path:     u.pathString(),
+		Expected: u.expectedType(),
+		Event:    event,
+		Type:     bt,
+	}
+}
@@ -19,6 +19,8 @@
 package gotype
 
 import (
+	"math/bits"
+	"strconv"
 	"unsafe"
 
 	structform "github.com/elastic/go-structform"
@@ -599,6 +601,13 @@ func (u *unfolderBool) OnNil(ctx *unfoldCtx) error {
 
 func (u *unfolderBool) OnBool(ctx *unfoldCtx, v bool) error { return u.assign(ctx, v) }
 
+func (u *unfolderBool) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceBool(u, v)
+}
+func (u *unfolderBool) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceBool(u, bytes2Str(v))
+}
+
 func (u *unfolderString) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, "")
 }
@@ -608,11 +617,112 @@ func (u *unfolderString) OnStringRef(ctx *unfoldCtx, v []byte) error {
 	return u.OnString(ctx, string(v))
 }
 
+func (u *unfolderString) OnBool(ctx *unfoldCtx, v bool) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatBool(v))
+}
+
+func (u *unfolderString) OnByte(ctx *unfoldCtx, v byte) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnUint(ctx *unfoldCtx, v uint) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *unfolderString) OnInt(ctx *unfoldCtx, v int) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderString) OnInt8(ctx *unfoldCtx, v int8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderString) OnInt16(ctx *unfoldCtx, v int16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderString) OnInt32(ctx *unfoldCtx, v int32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderString) OnInt64(ctx *unfoldCtx, v int64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *unfolderString) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+func (u *unfolderString) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.assign(ctx, strconv.FormatFloat(float64(v), 'g', -1, 64))
+}
+
 func (u *unfolderUint) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderUint) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
@@ -621,49 +731,89 @@ func (u *unfolderUint) OnUint(ctx *unfoldCtx, v uint) error {
 }
 
 func (u *unfolderUint) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
 func (u *unfolderUint) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint(v))
 }
 
+func (u *unfolderUint) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderUint) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderUint8) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
@@ -673,6 +823,9 @@ func (u *unfolderUint8) OnByte(ctx *unfoldCtx, v byte) error {
 }
 
 func (u *unfolderUint8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
@@ -681,58 +834,104 @@ func (u *unfolderUint8) OnUint8(ctx *unfoldCtx, v uint8) error {
 }
 
 func (u *unfolderUint8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
 func (u *unfolderUint8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint8(v))
 }
 
+func (u *unfolderUint8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderUint8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderUint16) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderUint16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
@@ -741,58 +940,104 @@ func (u *unfolderUint16) OnUint16(ctx *unfoldCtx, v uint16) error {
 }
 
 func (u *unfolderUint16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
 func (u *unfolderUint16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint16(v))
 }
 
+func (u *unfolderUint16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderUint16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderUint32) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderUint32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
@@ -801,58 +1046,104 @@ func (u *unfolderUint32) OnUint32(ctx *unfoldCtx, v uint32) error {
 }
 
 func (u *unfolderUint32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
 func (u *unfolderUint32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint32(v))
 }
 
+func (u *unfolderUint32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderUint32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderUint64) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderUint64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
@@ -861,58 +1152,104 @@ func (u *unfolderUint64) OnUint64(ctx *unfoldCtx, v uint64) error {
 }
 
 func (u *unfolderUint64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
 func (u *unfolderUint64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, uint64(v))
 }
 
+func (u *unfolderUint64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderUint64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderInt) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderInt) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
@@ -921,58 +1258,104 @@ func (u *unfolderInt) OnInt(ctx *unfoldCtx, v int) error {
 }
 
 func (u *unfolderInt) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
 func (u *unfolderInt) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.assign(ctx, int(v))
 }
 
+func (u *unfolderInt) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderInt) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderInt8) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderInt8) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
@@ -981,58 +1364,104 @@ func (u *unfolderInt8) OnInt8(ctx *unfoldCtx, v int8) error {
 }
 
 func (u *unfolderInt8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
 func (u *unfolderInt8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.assign(ctx, int8(v))
 }
 
+func (u *unfolderInt8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderInt8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderInt16) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderInt16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
@@ -1041,58 +1470,104 @@ func (u *unfolderInt16) OnInt16(ctx *unfoldCtx, v int16) error {
 }
 
 func (u *unfolderInt16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
 func (u *unfolderInt16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.assign(ctx, int16(v))
 }
 
+func (u *unfolderInt16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderInt16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderInt32) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderInt32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
@@ -1101,58 +1576,104 @@ func (u *unfolderInt32) OnInt32(ctx *unfoldCtx, v int32) error {
 }
 
 func (u *unfolderInt32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
 func (u *unfolderInt32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, int32(v))
 }
 
+func (u *unfolderInt32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderInt32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderInt64) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderInt64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
@@ -1161,58 +1682,104 @@ func (u *unfolderInt64) OnInt64(ctx *unfoldCtx, v int64) error {
 }
 
 func (u *unfolderInt64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
 func (u *unfolderInt64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, int64(v))
 }
 
+func (u *unfolderInt64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderInt64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderFloat32) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderFloat32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
 func (u *unfolderFloat32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
@@ -1221,58 +1788,104 @@ func (u *unfolderFloat32) OnFloat32(ctx *unfoldCtx, v float32) error {
 }
 
 func (u *unfolderFloat32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.assign(ctx, float32(v))
 }
 
+func (u *unfolderFloat32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderFloat32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *unfolderFloat64) OnNil(ctx *unfoldCtx) error {
 	return u.assign(ctx, 0)
 }
 
 func (u *unfolderFloat64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
 func (u *unfolderFloat64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.assign(ctx, float64(v))
 }
 
@@ -1280,6 +1893,13 @@ func (u *unfolderFloat64) OnFloat64(ctx *unfoldCtx, v float64) error {
 	return u.assign(ctx, float64(v))
 }
 
+func (u *unfolderFloat64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *unfolderFloat64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 /*
 func (*unfolderIfc) OnArrayStart(ctx *unfoldCtx, l int, bt structform.BaseType) error {
   return unfoldIfcStartSubArray(ctx, l, bt)
@@ -184,12 +184,11 @@ func (u *unfolderReflSlice) OnChildObjectDone(ctx *unfoldCtx) error {
 }
 
 func (u *unfolderReflMapOnElem) OnNil(ctx *unfoldCtx) error {
-	ptr := ctx.value.current
-	m := ptr.Elem()
+	m := u.shared.mapValue(ctx)
 	v := reflect.Zero(m.Type().Elem())
-	m.SetMapIndex(reflect.ValueOf(ctx.key.pop()), v)
+	m.SetMapIndex(u.shared.popKey(ctx), v)
 
-	ctx.unfolder.current = u.shared.waitKey
+	u.next(ctx)
 	return nil
 }
 
@@ -41,6 +41,15 @@ type unfolderReflMap struct {
 type unfolderReflMapShared struct {
 	waitKey  *unfolderReflMapOnKey
 	waitElem *unfolderReflMapOnElem
+
+	// keyType is set if map keys implement encoding.TextUnmarshaler. Decoded
+	// keys are pushed onto the value stack, on top of the map.
+	keyType reflect.Type
+
+	// remain is set if the map collects the unknown keys of a struct. The
+	// struct unfolder pushes the map for each unknown key, such that the map
+	// must be removed from the stack once the value has been unfolded.
+	remain bool
 }
 
 type unfolderReflMapStart struct {
@@ -80,12 +89,10 @@ func newUnfolderReflSlice(elem reflUnfolder) *unfolderReflSlice {
 func (u *unfolderReflSlice) initState(ctx *unfoldCtx, v reflect.Value) {
 	ctx.value.push(v)
 	ctx.unfolder.push(u)
-	ctx.idx.push(0)
 	ctx.unfolder.push(_singletonUnfolderReflSliceStart)
 }
 
 func (u *unfolderReflSlice) cleanup(ctx *unfoldCtx) {
-	ctx.idx.pop()
 	ctx.value.pop()
 	ctx.unfolder.pop()
 }
@@ -134,10 +141,7 @@ func (u *unfolderReflSlice) prepare(ctx *unfoldCtx) reflect.Value {
 		v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
 	}
 
-	elem := v.Index(idx.current).Addr()
-	idx.current++
-
-	return elem
+	return v.Index(idx.current).Addr()
 }
 
 func (u *unfolderReflSlice) OnObjectFinished(_ *unfoldCtx) error {
@@ -158,12 +162,24 @@ func (u *unfolderReflMap) initState(ctx *unfoldCtx, v reflect.Value) {
 }
 
 func (u *unfolderReflMapStart) OnObjectStart(ctx *unfoldCtx, l int, bt structform.BaseType) error {
+	if m := ctx.value.current.Elem(); m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
 	ctx.unfolder.pop()
 	return nil
 }
 
 func (u *unfolderReflMapOnKey) OnKey(ctx *unfoldCtx, key string) error {
-	ctx.key.push(key)
+	ctx.key.current = key
+	if kt := u.shared.keyType; kt != nil {
+		k, err := unmarshalTextKey(kt, key)
+		if err != nil {
+			return err
+		}
+		ctx.value.push(k)
+	}
+
 	ctx.unfolder.current = u.shared.waitElem
 	return nil
 }
@@ -178,9 +194,23 @@ func (u *unfolderReflMapOnKey) OnObjectFinished(ctx *unfoldCtx) error {
 	return nil
 }
 
-func (u *unfolderReflMapOnElem) prepare(ctx *unfoldCtx) reflect.Value {
+func (s *unfolderReflMapShared) mapValue(ctx *unfoldCtx) reflect.Value {
 	ptr := ctx.value.current
-	v := ptr.Elem()
+	if s.keyType != nil {
+		ptr = ctx.value.stack[len(ctx.value.stack)-1]
+	}
+	return ptr.Elem()
+}
+
+func (s *unfolderReflMapShared) popKey(ctx *unfoldCtx) reflect.Value {
+	if s.keyType != nil {
+		return ctx.value.pop()
+	}
+	return reflect.ValueOf(ctx.key.current)
+}
+
+func (u *unfolderReflMapOnElem) prepare(ctx *unfoldCtx) reflect.Value {
+	v := u.shared.mapValue(ctx)
 	et := v.Type().Elem()
 
 	target := reflect.New(et)
@@ -192,10 +222,19 @@ func (u *unfolderReflMapOnElem) process(ctx *unfoldCtx) {
 	ptr := ctx.value.pop()
 	v := ptr.Elem()
 
-	ptr = ctx.value.current
-	m := ptr.Elem()
-	m.SetMapIndex(reflect.ValueOf(ctx.key.pop()), v)
+	m := u.shared.mapValue(ctx)
+	m.SetMapIndex(u.shared.popKey(ctx), v)
+
+	u.next(ctx)
+}
 
+// next prepares the unfolder for the next object key.
+func (u *unfolderReflMapOnElem) next(ctx *unfoldCtx) {
+	if u.shared.remain {
+		ctx.value.pop()
+		ctx.unfolder.pop()
+		return
+	}
 	ctx.unfolder.current = u.shared.waitKey
 }
 
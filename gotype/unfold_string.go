@@ -0,0 +1,125 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// unfolderStringField unfolds numbers and booleans encoded as strings into
+// fields having the `string` tag option.
+type unfolderStringField struct {
+	unfolderErrUnknown
+	kind reflect.Kind
+	elem func(ctx *unfoldCtx, ptr unsafe.Pointer)
+}
+
+// wrapStringFieldUnfolder wraps the field unfolder, such that numbers and
+// booleans are parsed from strings. The field unfolder is returned as is if
+// the field type is no number or boolean.
+func wrapStringFieldUnfolder(
+	t reflect.Type,
+	elem func(*unfoldCtx, unsafe.Pointer),
+) func(*unfoldCtx, unsafe.Pointer) {
+	_, bt := baseType(t)
+	if !isStringEncodable(bt.Kind()) {
+		return elem
+	}
+
+	u := &unfolderStringField{kind: bt.Kind(), elem: elem}
+	return u.initState
+}
+
+func isStringEncodable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (u *unfolderStringField) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
+	ctx.unfolder.push(u)
+	ctx.ptr.push(ptr)
+}
+
+// startElem replaces the string parser with the field unfolder.
+func (u *unfolderStringField) startElem(ctx *unfoldCtx) unfolder {
+	ctx.unfolder.pop()
+	u.elem(ctx, ctx.ptr.pop())
+	return ctx.unfolder.current
+}
+
+func (u *unfolderStringField) OnNil(ctx *unfoldCtx) error {
+	return u.startElem(ctx).OnNil(ctx)
+}
+
+func (u *unfolderStringField) OnString(ctx *unfoldCtx, v string) error {
+	elem := u.startElem(ctx)
+	if u.kind == reflect.Bool {
+		return unfoldBoolString(ctx, elem, v)
+	}
+	return unfoldNumberString(ctx, elem, v)
+}
+
+func (u *unfolderStringField) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return u.OnString(ctx, bytes2Str(v))
+}
+
+// coerceNumber parses a number from a string, if coercion is enabled.
+func (c *unfoldCtx) coerceNumber(u unfolder, v string) error {
+	if !c.opts.coerce {
+		return errUnsupported
+	}
+	return unfoldNumberString(c, u, v)
+}
+
+// coerceBool parses a boolean from a string, if coercion is enabled.
+func (c *unfoldCtx) coerceBool(u unfolder, v string) error {
+	if !c.opts.coerce {
+		return errUnsupported
+	}
+	return unfoldBoolString(c, u, v)
+}
+
+func unfoldNumberString(ctx *unfoldCtx, u unfolder, v string) error {
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return u.OnInt64(ctx, i)
+	}
+	if i, err := strconv.ParseUint(v, 10, 64); err == nil {
+		return u.OnUint64(ctx, i)
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return u.OnFloat64(ctx, f)
+	}
+	return fmt.Errorf("'%v' is no valid number", v)
+}
+
+func unfoldBoolString(ctx *unfoldCtx, u unfolder, v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("'%v' is no valid boolean", v)
+	}
+	return u.OnBool(ctx, b)
+}
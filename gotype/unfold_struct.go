@@ -20,6 +20,7 @@ package gotype
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -28,9 +29,29 @@ import (
 	structform "github.com/elastic/go-structform"
 )
 
+// Defaulter is implemented by structs initializing default values. Defaults
+// is called on the struct before the fields are unfolded.
+type Defaulter interface {
+	Defaults()
+}
+
 type unfolderStruct struct {
 	unfolderErrExpectKey
 	fields map[string]fieldUnfolder
+
+	// folded indexes fields by lower case name, if case insensitive matching
+	// is enabled. Ambiguous names map to nil.
+	folded map[string]*fieldUnfolder
+
+	// remain collects unknown keys, if the struct has a field tagged with
+	// `remain`.
+	remain *remainField
+
+	// checks lists the fields marked as required or having a default value.
+	checks []fieldCheck
+
+	// typ is set if the struct implements Defaulter
+	typ reflect.Type
 }
 
 type unfolderStructStart struct {
@@ -38,8 +59,33 @@ type unfolderStructStart struct {
 }
 
 type fieldUnfolder struct {
+	// name is the object key matching the field, reported in error paths
+	name string
+
 	offset    uintptr
 	initState func(ctx *unfoldCtx, sp unsafe.Pointer)
+
+	// check is the index of the field in the list of checked fields, or -1 if
+	// the field is not checked
+	check int
+}
+
+// fieldCheck is evaluated when the struct is finished, if the field has not
+// been unfolded.
+type fieldCheck struct {
+	name     string
+	required bool
+	field    fieldUnfolder
+
+	// def feeds the default value to the field unfolder, if configured
+	def func(ctx *unfoldCtx) error
+}
+
+// remainField unfolds unknown keys into a map[string]T field.
+type remainField struct {
+	offset   uintptr
+	mapType  reflect.Type
+	unfolder *unfolderReflMap
 }
 
 var (
@@ -54,19 +100,219 @@ func createUnfolderReflStruct(ctx *unfoldCtx, t reflect.Type) (*unfolderStruct,
 	// assume t is pointer to struct
 	t = t.Elem()
 
-	fields, err := fieldUnfolders(ctx, t)
-	if err != nil {
+	u := &unfolderStruct{}
+	if err := u.initFields(ctx, t); err != nil {
 		return nil, err
 	}
 
-	u := &unfolderStruct{fields: fields}
+	if ctx.opts.caseInsensitive {
+		u.folded = foldFieldNames(u.fields)
+	}
+	if reflect.PtrTo(t).Implements(tDefaulter) {
+		u.typ = t
+	}
 	return u, nil
 }
 
-func fieldUnfolders(ctx *unfoldCtx, t reflect.Type) (map[string]fieldUnfolder, error) {
-	count := t.NumField()
+func (u *unfolderStruct) initFields(ctx *unfoldCtx, t reflect.Type) error {
 	fields := map[string]fieldUnfolder{}
 
+	err := walkStructFields(ctx, t, 0, func(name string, st reflect.StructField, tagOpts tagOptions, offset uintptr) error {
+		if tagOpts.remain {
+			if u.remain != nil {
+				return errDuplicateRemain
+			}
+
+			var err error
+			u.remain, err = makeRemainField(ctx, st.Type, offset)
+			return err
+		}
+
+		fu, err := makeFieldUnfolder(ctx, st)
+		if err != nil {
+			return err
+		}
+		fu.offset = offset
+		if tagOpts.asString {
+			fu.initState = wrapStringFieldUnfolder(st.Type, fu.initState)
+		}
+
+		if tagOpts.required || tagOpts.hasDef {
+			check := fieldCheck{name: name, required: tagOpts.required, field: fu}
+			if tagOpts.hasDef {
+				check.def = makeDefaultValue(st.Type, tagOpts.def, tagOpts.asString)
+				if err := checkDefaultValue(ctx, st.Type, fu, check.def); err != nil {
+					return fmt.Errorf("invalid default value '%v' for field %v: %v", tagOpts.def, name, err)
+				}
+			}
+
+			fu.check = len(u.checks)
+			u.checks = append(u.checks, check)
+		}
+
+		for _, name := range append([]string{name}, tagOpts.aliases...) {
+			if _, exists := fields[name]; exists {
+				return fmt.Errorf("duplicate field name %v", name)
+			}
+			fu.name = name
+			fields[name] = fu
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.fields = fields
+	return nil
+}
+
+// makeDefaultValue creates the event for unfolding the default literal into
+// a field of type t. The literal is reported as string if it can not be
+// parsed into the fields base type, such that types implementing
+// encoding.TextUnmarshaler or user unfolders can parse the literal. Fields
+// using the `string` option always receive the literal as string.
+func makeDefaultValue(t reflect.Type, lit string, asString bool) func(ctx *unfoldCtx) error {
+	onString := func(ctx *unfoldCtx) error {
+		return ctx.unfolder.current.OnString(ctx, lit)
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if asString && isStringEncodable(t.Kind()) {
+		return onString
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(lit); err == nil {
+			return func(ctx *unfoldCtx) error { return ctx.unfolder.current.OnBool(ctx, b) }
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(lit, 0, 64); err == nil {
+			return func(ctx *unfoldCtx) error { return ctx.unfolder.current.OnInt64(ctx, i) }
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseUint(lit, 0, 64); err == nil {
+			return func(ctx *unfoldCtx) error { return ctx.unfolder.current.OnUint64(ctx, i) }
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(lit, 64); err == nil {
+			return func(ctx *unfoldCtx) error { return ctx.unfolder.current.OnFloat64(ctx, f) }
+		}
+	}
+	return onString
+}
+
+// checkDefaultValue unfolds the default value into a temporary value of type
+// t, such that unusable defaults are reported when the unfolder is created.
+func checkDefaultValue(ctx *unfoldCtx, t reflect.Type, fu fieldUnfolder, def func(ctx *unfoldCtx) error) error {
+	tmp := &unfoldCtx{opts: ctx.opts, userReg: ctx.userReg, reg: ctx.reg}
+	tmp.unfolder.init(&unfolderNoTarget{})
+	tmp.value.init(reflect.Value{})
+	tmp.ptr.init()
+	tmp.seen.init()
+	tmp.baseType.init()
+	tmp.valueBuffer.init()
+	tmp.initPath(nil)
+
+	v := reflect.New(t)
+	fu.initState(tmp, unsafe.Pointer(v.Pointer()))
+	return def(tmp)
+}
+
+func makeRemainField(ctx *unfoldCtx, t reflect.Type, offset uintptr) (*remainField, error) {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return nil, errRemainNeedsMap
+	}
+
+	et := t.Elem()
+	elem := lookupReflUser(ctx, et)
+	if elem == nil {
+		var err error
+		elem, err = lookupReflUnfolder(ctx, reflect.PtrTo(et), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := newUnfolderReflMap(elem)
+	u.shared.remain = true
+	return &remainField{offset: offset, mapType: t, unfolder: u}, nil
+}
+
+// initState prepares the remain map to receive the value for key. The map is
+// allocated if required.
+func (r *remainField) initState(ctx *unfoldCtx, structPtr unsafe.Pointer, key string) {
+	fieldPtr := unsafe.Pointer(uintptr(structPtr) + r.offset)
+	ptr := reflect.NewAt(r.mapType, fieldPtr)
+	if m := ptr.Elem(); m.IsNil() {
+		m.Set(reflect.MakeMap(r.mapType))
+	}
+
+	ctx.value.push(ptr)
+	ctx.key.current = key
+	ctx.unfolder.push(r.unfolder.shared.waitElem)
+}
+
+// foldFieldNames indexes fields by their lower case names. Names matching
+// multiple fields are marked as ambiguous by a nil entry.
+func foldFieldNames(fields map[string]fieldUnfolder) map[string]*fieldUnfolder {
+	folded := map[string]*fieldUnfolder{}
+	for name := range fields {
+		fu := fields[name]
+		lower := strings.ToLower(name)
+		if other, exists := folded[lower]; exists {
+			if other == nil || other.offset != fu.offset {
+				folded[lower] = nil
+			}
+			continue
+		}
+		folded[lower] = &fu
+	}
+	return folded
+}
+
+// fieldType returns the type of the field unfolded for key, or nil if the
+// struct has no such field.
+func fieldType(ctx *unfoldCtx, t reflect.Type, key string) reflect.Type {
+	var found, remain reflect.Type
+	walkStructFields(ctx, t, 0, func(name string, st reflect.StructField, tagOpts tagOptions, _ uintptr) error {
+		if found != nil {
+			return nil
+		}
+		if tagOpts.remain {
+			if st.Type.Kind() == reflect.Map {
+				remain = st.Type.Elem()
+			}
+			return nil
+		}
+
+		for _, name := range append([]string{name}, tagOpts.aliases...) {
+			if name == key || (ctx.opts.caseInsensitive && strings.EqualFold(name, key)) {
+				found = st.Type
+				break
+			}
+		}
+		return nil
+	})
+	if found == nil {
+		return remain
+	}
+	return found
+}
+
+// walkStructFields calls fn for each exported field of t to be unfolded,
+// passing the field name, the field tag options and the field offset relative
+// to the outer struct. Squashed structs are walked recursively.
+func walkStructFields(
+	ctx *unfoldCtx,
+	t reflect.Type,
+	base uintptr,
+	fn func(name string, st reflect.StructField, tagOpts tagOptions, offset uintptr) error,
+) error {
+	count := t.NumField()
 	for i := 0; i < count; i++ {
 		st := t.Field(i)
 
@@ -76,61 +322,47 @@ func fieldUnfolders(ctx *unfoldCtx, t reflect.Type) (map[string]fieldUnfolder, e
 			continue
 		}
 
-		tagName, tagOpts := parseTags(st.Tag.Get(ctx.opts.tag))
+		tagName, tagOpts := parseTags(ctx.opts.fieldTag(st))
 		if tagOpts.omit {
 			continue
 		}
 
 		if tagOpts.squash {
 			if st.Type.Kind() != reflect.Struct {
-				return nil, errSquashNeedObject
+				return errSquashNeedObject
 			}
 
-			sub, err := fieldUnfolders(ctx, st.Type)
-			if err != nil {
-				return nil, err
+			if err := walkStructFields(ctx, st.Type, base+st.Offset, fn); err != nil {
+				return err
 			}
+			continue
+		}
 
-			for name, fu := range sub {
-				fu.offset += st.Offset
-				if _, exists := fields[name]; exists {
-					return nil, fmt.Errorf("duplicate field name %v", name)
-				}
-
-				fields[name] = fu
-			}
+		if tagName != "" {
+			name = tagName
 		} else {
-			if tagName != "" {
-				name = tagName
-			} else {
-				name = strings.ToLower(name)
-			}
-
-			if _, exists := fields[name]; exists {
-				return nil, fmt.Errorf("duplicate field name %v", name)
-			}
-
-			fu, err := makeFieldUnfolder(ctx, st)
-			if err != nil {
-				return nil, err
-			}
+			name = ctx.opts.fieldName(name)
+		}
 
-			fields[name] = fu
+		if err := fn(name, st, tagOpts, base+st.Offset); err != nil {
+			return err
 		}
 	}
 
-	return fields, nil
+	return nil
 }
 
 func makeFieldUnfolder(ctx *unfoldCtx, st reflect.StructField) (fieldUnfolder, error) {
-	fu := fieldUnfolder{offset: st.Offset}
+	fu := fieldUnfolder{offset: st.Offset, check: -1}
 	targetType := reflect.PtrTo(st.Type)
 
 	if uu := lookupReflUser(ctx, targetType); uu != nil {
 		fu.initState = wrapReflUnfolder(st.Type, uu)
 	} else if targetType.Implements(tExpander) {
 		fu.initState = wrapReflUnfolder(st.Type, newExpanderInit())
-	} else if pu := lookupGoPtrUnfolder(st.Type); pu != nil {
+	} else if mu := lookupReflUnmarshaler(ctx, targetType); mu != nil {
+		fu.initState = wrapReflUnfolder(st.Type, mu)
+	} else if pu := lookupGoPtrUnfolder(st.Type); pu != nil && !hasUnmarshalerElem(ctx, st.Type) {
 		fu.initState = pu.initState
 	} else {
 		ru, err := lookupReflUnfolder(ctx, targetType, false)
@@ -160,6 +392,13 @@ func (u *unfolderStruct) initState(ctx *unfoldCtx, v reflect.Value) {
 }
 
 func (u *unfolderStruct) initStatePtr(ctx *unfoldCtx, ptr unsafe.Pointer) {
+	if u.typ != nil {
+		reflect.NewAt(u.typ, ptr).Interface().(Defaulter).Defaults()
+	}
+	if len(u.checks) > 0 {
+		ctx.seen.push(make([]bool, len(u.checks)))
+	}
+
 	ctx.ptr.push(ptr)
 	ctx.unfolder.push(u)
 	ctx.unfolder.push(_singletonUnfolderStructStart)
@@ -171,25 +410,91 @@ func (u *unfolderStructStart) OnObjectStart(ctx *unfoldCtx, l int, bt structform
 }
 
 func (u *unfolderStruct) OnObjectFinished(ctx *unfoldCtx) error {
+	if len(u.checks) > 0 {
+		if err := u.checkFields(ctx, ctx.seen.pop()); err != nil {
+			return err
+		}
+	}
+
 	ctx.unfolder.pop()
 	ctx.ptr.pop()
 	return nil
 }
 
+// checkFields reports missing required fields and unfolds the default values
+// of fields not present in the object.
+func (u *unfolderStruct) checkFields(ctx *unfoldCtx, seen []bool) error {
+	structPtr := ctx.ptr.current
+	for i := range u.checks {
+		check := &u.checks[i]
+		if seen[i] {
+			continue
+		}
+
+		if check.required {
+			// report the missing field in the error path
+			ctx.setPathKey(check.name)
+			return errMissingRequired
+		}
+
+		fieldPtr := unsafe.Pointer(uintptr(structPtr) + check.field.offset)
+		check.field.initState(ctx, fieldPtr)
+		if err := check.def(ctx); err != nil {
+			ctx.setPathKey(check.name)
+			return err
+		}
+	}
+	return nil
+}
+
 func (u *unfolderStruct) OnChildObjectDone(ctx *unfoldCtx) error { return nil }
 func (u *unfolderStruct) OnChildArrayDone(ctx *unfoldCtx) error  { return nil }
 
 func (u *unfolderStruct) OnKeyRef(ctx *unfoldCtx, key []byte) error {
+	if u.remain != nil {
+		// unknown keys are stored in the remain map, requiring a copy of key
+		return u.OnKey(ctx, ctx.keyCache.get(key))
+	}
 	return u.OnKey(ctx, bytes2Str(key))
 }
 
 func (u *unfolderStruct) OnKey(ctx *unfoldCtx, key string) error {
 	field, exists := u.fields[key]
+	if !exists && u.folded != nil {
+		var folded *fieldUnfolder
+		folded, exists = u.folded[strings.ToLower(key)]
+		if exists && folded == nil {
+			return fmt.Errorf("ambiguous field name '%v'", key)
+		}
+		if exists {
+			field = *folded
+		}
+	}
+
+	if !exists && u.remain != nil {
+		u.remain.initState(ctx, ctx.ptr.current, key)
+		return nil
+	}
+
 	if !exists {
+		switch ctx.opts.unknownFields {
+		case UnknownFieldFail:
+			return errUnknownField
+		case UnknownFieldCollect:
+			ctx.setPathKey(key)
+			ctx.unknownFields = append(ctx.unknownFields, ctx.pathString())
+			ctx.setPathKey("")
+		}
+
 		_ignoredField.initState(ctx, nil)
 		return nil
 	}
 
+	ctx.setPathKey(field.name)
+	if field.check >= 0 {
+		ctx.seen.current[field.check] = true
+	}
+
 	structPtr := ctx.ptr.current
 	fieldPtr := unsafe.Pointer(uintptr(structPtr) + field.offset)
 	field.initState(ctx, fieldPtr)
@@ -19,13 +19,19 @@ package gotype
 
 import (
 	"bytes"
+	gojson "encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	structform "github.com/elastic/go-structform"
 	"github.com/elastic/go-structform/json"
 )
 
@@ -629,3 +635,971 @@ func TestUserUnfold(t *testing.T) {
 		})
 	}
 }
+
+func TestUnfoldErrorPath(t *testing.T) {
+	type output struct {
+		Hosts []string
+		Port  int
+	}
+	type config struct {
+		Name    string
+		Outputs []output
+		Labels  map[string]int
+	}
+
+	cases := map[string]struct {
+		json     string
+		path     string
+		expected reflect.Type
+		event    string
+		err      error
+	}{
+		"array element": {
+			json:     `{"outputs": [{}, {}, {"hosts": ["a", 1]}]}`,
+			path:     "outputs[2].hosts[1]",
+			expected: reflect.TypeOf(""),
+			event:    "OnInt64",
+			err:      errUnsupported,
+		},
+		"nested array element": {
+			json:     `{"outputs": [{"hosts": ["a"]}, {"hosts": ["b", "c", 3]}]}`,
+			path:     "outputs[1].hosts[2]",
+			expected: reflect.TypeOf(""),
+			event:    "OnInt64",
+			err:      errUnsupported,
+		},
+		"struct field": {
+			json:     `{"name": "test", "outputs": [{"port": "9200"}]}`,
+			path:     "outputs[0].port",
+			expected: reflect.TypeOf(0),
+			event:    "OnString",
+			err:      errUnsupported,
+		},
+		"map value": {
+			json:     `{"labels": {"a": 1, "b": true}}`,
+			path:     "labels.b",
+			expected: reflect.TypeOf(0),
+			event:    "OnBool",
+			err:      errUnsupported,
+		},
+		"object into array": {
+			json:     `{"outputs": {}}`,
+			path:     "outputs",
+			expected: reflect.TypeOf([]output{}),
+			event:    "OnObjectStart",
+			err:      errExpectedArray,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to config
+			un, err := NewUnfolder(&to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = json.ParseString(test.json, un)
+			if serr, ok := err.(*structform.SyntaxError); ok {
+				err = serr.Err
+			}
+
+			uerr, ok := err.(*UnfoldError)
+			if !ok {
+				t.Fatalf("expected unfold error, got: %v", err)
+			}
+			assert.Equal(t, test.path, uerr.Path)
+			assert.Equal(t, test.expected, uerr.Expected)
+			assert.Equal(t, test.event, uerr.Event)
+			assert.Equal(t, test.err, uerr.Err)
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		err := un.OnObjectStart(-1, structform.AnyType)
+		if err == nil {
+			err = un.OnKey("name")
+		}
+		if err == nil {
+			err = un.OnBool(true)
+		}
+		assert.EqualError(t, err, "failed to unfold OnBool(BoolType) into string at 'name': unsupported")
+	})
+}
+
+func TestUnfoldIgnoreUnknownFields(t *testing.T) {
+	type config struct {
+		Name string
+	}
+
+	tests := map[string]string{
+		"string value": `{"unknown": "x", "name": "test"}`,
+		"object value": `{"unknown": {"a": "x", "b": 1}, "name": "test"}`,
+		"array value":  `{"unknown": ["x", {"a": "y"}, [1]], "name": "test"}`,
+		"nested value": `{"unknown": {"a": {"b": ["x"]}, "c": "y"}, "name": "test"}`,
+	}
+
+	for name, in := range tests {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			var to config
+			un, err := NewUnfolder(&to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.ParseString(in, un); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, config{Name: "test"}, to)
+		})
+	}
+}
+
+func TestUnfoldTime(t *testing.T) {
+	ts := time.Date(2020, 2, 3, 4, 5, 6, 7000000, time.UTC)
+
+	type event struct {
+		Timestamp time.Time
+		Took      time.Duration
+		Times     []time.Time
+		Retry     *time.Duration
+	}
+
+	unfoldJSON := func(to interface{}, in string) error {
+		un, err := NewUnfolder(to)
+		if err != nil {
+			return err
+		}
+		return json.ParseString(in, un)
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var to event
+		err := unfoldJSON(&to, `{
+			"timestamp": "2020-02-03T04:05:06.007Z",
+			"took": "1m30s",
+			"times": ["2020-02-03T04:05:06.007Z"],
+			"retry": 1000
+		}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		retry := time.Microsecond
+		assert.Equal(t, event{
+			Timestamp: ts,
+			Took:      90 * time.Second,
+			Times:     []time.Time{ts},
+			Retry:     &retry,
+		}, to)
+	})
+
+	t.Run("epoch", func(t *testing.T) {
+		cases := map[string]struct {
+			enc  TimeEncoding
+			json string
+			want time.Time
+		}{
+			"seconds":         {TimeEpochSeconds, `1580702706`, time.Unix(1580702706, 0).UTC()},
+			"fractional secs": {TimeEpochSeconds, `1580702706.5`, time.Unix(1580702706, 5e8).UTC()},
+			"millis":          {TimeEpochMillis, `1580702706007`, ts},
+			"nanos":           {TimeEpochNanos, `1580702706007000000`, ts},
+			"string":          {TimeEpochMillis, `"2020-02-03T04:05:06.007Z"`, ts},
+		}
+
+		for name, test := range cases {
+			test := test
+			t.Run(name, func(t *testing.T) {
+				var to time.Time
+				un, err := NewUnfolder(&to, UnfoldTime(test.enc))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.ParseString(test.json, un); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.want, to)
+			})
+		}
+	})
+
+	t.Run("layout", func(t *testing.T) {
+		var to time.Time
+		un, _ := NewUnfolder(&to, UnfoldTime(TimeLayout("2006-01-02")))
+		if err := json.ParseString(`"2020-02-03"`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("number without epoch encoding", func(t *testing.T) {
+		var to time.Time
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(`1580702706`, un)
+		if uerr, ok := err.(*UnfoldError); assert.True(t, ok) {
+			assert.Equal(t, errUnexpectedNum, uerr.Err)
+		}
+	})
+
+	t.Run("keep zone offset", func(t *testing.T) {
+		var to time.Time
+		if err := unfoldJSON(&to, `"2020-02-03T06:05:06.007+02:00"`); err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, ts.Equal(to))
+		_, offset := to.Zone()
+		assert.Equal(t, 2*60*60, offset)
+		assert.Equal(t, "2020-02-03T06:05:06.007+02:00", to.Format(time.RFC3339Nano))
+	})
+
+	t.Run("duration from float", func(t *testing.T) {
+		var to time.Duration
+		if err := unfoldJSON(&to, `1e9`); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, time.Second, to)
+
+		for _, in := range []string{`1.5`, `1e19`, `-1e19`} {
+			assert.Error(t, unfoldJSON(&to, in), in)
+		}
+
+		un, _ := NewUnfolder(&to)
+		assert.Error(t, un.OnFloat64(math.NaN()))
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		in := event{Timestamp: ts, Took: time.Hour, Times: []time.Time{ts, ts}}
+
+		buf := bytes.NewBuffer(nil)
+		if err := Fold(in, json.NewVisitor(buf)); err != nil {
+			t.Fatal(err)
+		}
+
+		var out event
+		if err := unfoldJSON(&out, buf.String()); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, in, out)
+	})
+}
+
+func TestUnfoldTextUnmarshaler(t *testing.T) {
+	type event struct {
+		Level  textLevel
+		Source net.IP
+		Count  big.Int
+		Max    *big.Int
+		Levels []textLevel
+		Counts map[textLevel]int
+	}
+
+	var to event
+	un, err := NewUnfolder(&to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = json.ParseString(`{
+		"level": "debug",
+		"source": "192.168.0.1",
+		"count": "18446744073709551616",
+		"max": "42",
+		"levels": ["info", "debug"],
+		"counts": {"debug": 2}
+	}`, un)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, _ := new(big.Int).SetString("18446744073709551616", 10)
+	assert.Equal(t, textLevel(1), to.Level)
+	assert.True(t, net.IPv4(192, 168, 0, 1).Equal(to.Source))
+	assert.Equal(t, 0, n.Cmp(&to.Count))
+	assert.Equal(t, big.NewInt(42), to.Max)
+	assert.Equal(t, []textLevel{0, 1}, to.Levels)
+	assert.Equal(t, map[textLevel]int{1: 2}, to.Counts)
+
+	t.Run("invalid value", func(t *testing.T) {
+		var to event
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(`{"level": "trace"}`, un)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid level 'trace'")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		var to map[textLevel]string
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(`{"trace": "x"}`, un)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid level 'trace'")
+	})
+
+	t.Run("primitive kind requires text", func(t *testing.T) {
+		var to textLevel
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(`1`, un)
+		assert.Error(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var to textLevel
+		un, _ := NewUnfolder(&to, UnfoldTextUnmarshaler(false))
+		if err := json.ParseString(`1`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, textLevel(1), to)
+	})
+}
+
+func TestUnfoldJSONUnmarshaler(t *testing.T) {
+	type shape struct {
+		Origin jsonPoint
+		Points []jsonPoint
+		Named  map[string]jsonPoint
+		Ptr    *jsonPoint
+		Meta   gojson.RawMessage
+		Name   string
+	}
+
+	var to shape
+	un, err := NewUnfolder(&to, UnfoldJSONUnmarshaler(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = json.ParseString(`{
+		"origin": [1, 2],
+		"points": [[3, 4], [5, 6]],
+		"named": {"a": [7, 8]},
+		"ptr": [9, 10],
+		"meta": {"a": [1, "x", {"b": null}]},
+		"name": "test"
+	}`, un)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, shape{
+		Origin: jsonPoint{1, 2},
+		Points: []jsonPoint{{3, 4}, {5, 6}},
+		Named:  map[string]jsonPoint{"a": {7, 8}},
+		Ptr:    &jsonPoint{9, 10},
+		Meta:   gojson.RawMessage(`{"a":[1,"x",{"b":null}]}`),
+		Name:   "test",
+	}, to)
+
+	t.Run("error", func(t *testing.T) {
+		var to shape
+		un, _ := NewUnfolder(&to, UnfoldJSONUnmarshaler(true))
+		err := json.ParseString(`{"origin": {"x": 1}}`, un)
+		assert.Error(t, err)
+	})
+}
+
+func TestUnfoldTagsAndNaming(t *testing.T) {
+	type config struct {
+		HTTPServer string
+		Name       string `json:"display_name"`
+		Port       int    `json:"port" struct:"listen_port"`
+	}
+
+	tests := map[string]struct {
+		opts []UnfoldOption
+		json string
+		want config
+	}{
+		"default": {
+			json: `{"httpserver": "a", "name": "b", "listen_port": 80}`,
+			want: config{HTTPServer: "a", Name: "b", Port: 80},
+		},
+		"tag fallback": {
+			opts: []UnfoldOption{UnfoldTags("struct", "json"), UnfoldNaming(NameCamelCase)},
+			json: `{"httpServer": "a", "display_name": "b", "listen_port": 80, "port": 1}`,
+			want: config{HTTPServer: "a", Name: "b", Port: 80},
+		},
+		"snake case": {
+			opts: []UnfoldOption{UnfoldTags("json"), UnfoldNaming(NameSnakeCase)},
+			json: `{"http_server": "a", "display_name": "b", "port": 80}`,
+			want: config{HTTPServer: "a", Name: "b", Port: 80},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to config
+			un, err := NewUnfolder(&to, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.ParseString(test.json, un); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, to)
+		})
+	}
+}
+
+func TestUnfoldFieldMatching(t *testing.T) {
+	type config struct {
+		Hosts   []string `struct:"hosts,alias=host,alias=servers"`
+		Timeout int
+	}
+
+	type ambiguous struct {
+		A int `struct:"name"`
+		B int `struct:"Name"`
+	}
+
+	tests := map[string]struct {
+		opts []UnfoldOption
+		json string
+		want config
+	}{
+		"exact match": {
+			json: `{"hosts": ["a"], "timeout": 1}`,
+			want: config{Hosts: []string{"a"}, Timeout: 1},
+		},
+		"alias": {
+			json: `{"servers": ["a", "b"]}`,
+			want: config{Hosts: []string{"a", "b"}},
+		},
+		"case sensitive by default": {
+			json: `{"Hosts": ["a"], "TIMEOUT": 1, "Other": {"x": [1, {"y": "z"}]}}`,
+			want: config{},
+		},
+		"case insensitive": {
+			opts: []UnfoldOption{UnfoldCaseInsensitive(true)},
+			json: `{"Hosts": ["a"], "TIMEOUT": 1}`,
+			want: config{Hosts: []string{"a"}, Timeout: 1},
+		},
+		"case insensitive alias": {
+			opts: []UnfoldOption{UnfoldCaseInsensitive(true)},
+			json: `{"Host": ["a"]}`,
+			want: config{Hosts: []string{"a"}},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to config
+			un, err := NewUnfolder(&to, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.ParseString(test.json, un); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, to)
+		})
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		var to ambiguous
+		un, err := NewUnfolder(&to, UnfoldCaseInsensitive(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// exact matches are not ambiguous
+		if err := json.ParseString(`{"name": 1, "Name": 2}`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, ambiguous{A: 1, B: 2}, to)
+
+		un.SetTarget(&to)
+		err = json.ParseString(`{"NAME": 1}`, un)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "ambiguous field name 'NAME'")
+		}
+	})
+
+	t.Run("duplicate alias", func(t *testing.T) {
+		var to struct {
+			A int `struct:"a,alias=b"`
+			B int
+		}
+		_, err := NewUnfolder(&to)
+		assert.Error(t, err)
+	})
+}
+
+func TestUnfoldUnknownFields(t *testing.T) {
+	type output struct {
+		Hosts []string
+	}
+	type config struct {
+		Name    string
+		Outputs []output
+	}
+
+	input := `{"name": "test", "nmae": "x", "outputs": [{"hosts": ["a"]}, {"hsots": ["b"]}]}`
+
+	t.Run("ignore by default", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, config{Name: "test", Outputs: []output{{[]string{"a"}}, {}}}, to)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to, UnfoldUnknownFields(UnknownFieldFail))
+		err := json.ParseString(input, un)
+		if serr, ok := err.(*structform.SyntaxError); ok {
+			err = serr.Err
+		}
+
+		uerr, ok := err.(*UnfoldError)
+		if !ok {
+			t.Fatalf("expected unfold error, got: %v", err)
+		}
+		assert.Equal(t, "nmae", uerr.Path)
+		assert.Equal(t, errUnknownField, uerr.Err)
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to, UnfoldUnknownFields(UnknownFieldCollect))
+		err := json.ParseString(input, un)
+		if serr, ok := err.(*structform.SyntaxError); ok {
+			err = serr.Err
+		}
+
+		uerr, ok := err.(*UnknownFieldsError)
+		if !ok {
+			t.Fatalf("expected unknown fields error, got: %v", err)
+		}
+		assert.Equal(t, []string{"nmae", "outputs[1].hsots"}, uerr.Paths)
+		assert.EqualError(t, uerr, "unknown fields: 'nmae', 'outputs[1].hsots'")
+		assert.Equal(t, "test", to.Name)
+	})
+}
+
+func TestUnfoldRemain(t *testing.T) {
+	type inner struct {
+		Host string
+	}
+
+	t.Run("collect unknown keys", func(t *testing.T) {
+		type config struct {
+			Name  string
+			Extra map[string]interface{} `struct:",remain"`
+		}
+
+		var to config
+		un, _ := NewUnfolder(&to, UnfoldUnknownFields(UnknownFieldFail))
+		input := `{"name": "test", "a": 1, "b": {"c": [1, "x"]}, "d": null}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "test", to.Name)
+		assert.Equal(t, map[string]interface{}{
+			"a": int64(1),
+			"b": map[string]interface{}{"c": []interface{}{int64(1), "x"}},
+			"d": nil,
+		}, to.Extra)
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		type config struct {
+			Main   inner
+			Others map[string]inner `struct:",remain"`
+		}
+
+		var to config
+		un, _ := NewUnfolder(&to)
+		input := `{"main": {"host": "a"}, "b": {"host": "b"}, "c": {"host": "c"}}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, config{
+			Main:   inner{Host: "a"},
+			Others: map[string]inner{"b": {Host: "b"}, "c": {Host: "c"}},
+		}, to)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		type config struct {
+			Outputs []struct {
+				Host  string
+				Extra map[string]string `struct:",remain"`
+			}
+		}
+
+		var to config
+		un, _ := NewUnfolder(&to)
+		input := `{"outputs": [{"host": "a", "x": "1"}, {"y": "2", "host": "b"}]}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, to.Outputs, 2) {
+			assert.Equal(t, "a", to.Outputs[0].Host)
+			assert.Equal(t, map[string]string{"x": "1"}, to.Outputs[0].Extra)
+			assert.Equal(t, "b", to.Outputs[1].Host)
+			assert.Equal(t, map[string]string{"y": "2"}, to.Outputs[1].Extra)
+		}
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		type config struct {
+			Name  string
+			Extra map[string]interface{} `struct:",remain"`
+		}
+
+		in := config{Name: "test", Extra: map[string]interface{}{"a": "b"}}
+		buf := bytes.NewBuffer(nil)
+		if err := Fold(in, json.NewVisitor(buf)); err != nil {
+			t.Fatal(err)
+		}
+
+		var to config
+		un, _ := NewUnfolder(&to)
+		if err := json.Parse(buf.Bytes(), un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, in, to)
+	})
+
+	t.Run("requires string keyed map", func(t *testing.T) {
+		type config struct {
+			Extra map[int]string `struct:",remain"`
+		}
+
+		var to config
+		_, err := NewUnfolder(&to)
+		assert.Equal(t, errRemainNeedsMap, err)
+	})
+}
+
+type defaultsConfig struct {
+	Name    string
+	Retries int
+	Hosts   []string
+}
+
+func (c *defaultsConfig) Defaults() {
+	c.Retries = 3
+	c.Hosts = []string{"localhost"}
+}
+
+func TestUnfoldRequiredAndDefaults(t *testing.T) {
+	type output struct {
+		Host    string        `struct:"host,required"`
+		Port    int           `struct:"port,default=9200"`
+		Enabled *bool         `struct:"enabled,default=true"`
+		Timeout time.Duration `struct:"timeout,default=30s"`
+		Scheme  string        `struct:"scheme,default=http"`
+	}
+	type config struct {
+		Outputs []output
+	}
+
+	t.Run("apply defaults", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		input := `{"outputs": [{"host": "a"}, {"host": "b", "port": 9201, "enabled": false, "timeout": "1s", "scheme": "https"}]}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		enabled, disabled := true, false
+		assert.Equal(t, config{Outputs: []output{
+			{Host: "a", Port: 9200, Enabled: &enabled, Timeout: 30 * time.Second, Scheme: "http"},
+			{Host: "b", Port: 9201, Enabled: &disabled, Timeout: time.Second, Scheme: "https"},
+		}}, to)
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(`{"outputs": [{"host": "a"}, {"port": 9201}]}`, un)
+		if serr, ok := err.(*structform.SyntaxError); ok {
+			err = serr.Err
+		}
+
+		uerr, ok := err.(*UnfoldError)
+		if !ok {
+			t.Fatalf("expected unfold error, got: %v", err)
+		}
+		assert.Equal(t, errMissingRequired, uerr.Err)
+		assert.Equal(t, "outputs[1].host", uerr.Path)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		cases := map[string]interface{}{
+			"string into int": &struct {
+				Port int `struct:"port,default=http"`
+			}{},
+			"string into slice": &struct {
+				Hosts []string `struct:"hosts,default=a"`
+			}{},
+			"overflow": &struct {
+				Level uint8 `struct:"level,default=300"`
+			}{},
+		}
+
+		for name, to := range cases {
+			to := to
+			t.Run(name, func(t *testing.T) {
+				_, err := NewUnfolder(to)
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), "invalid default value")
+				}
+			})
+		}
+	})
+
+	t.Run("defaults hook", func(t *testing.T) {
+		var to struct {
+			Configs []defaultsConfig
+		}
+		un, _ := NewUnfolder(&to)
+		input := `{"configs": [{"name": "a"}, {"name": "b", "retries": 1, "hosts": ["x", "y"]}]}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []defaultsConfig{
+			{Name: "a", Retries: 3, Hosts: []string{"localhost"}},
+			{Name: "b", Retries: 1, Hosts: []string{"x", "y"}},
+		}, to.Configs)
+	})
+}
+
+func TestUnfoldNumberChecks(t *testing.T) {
+	type numbers struct {
+		I8  int8
+		U16 uint16
+		U   uint
+		I64 int64
+		F32 float32
+		Arr []uint8
+		Map map[string]int16
+	}
+
+	valid := map[string]struct {
+		input string
+		want  numbers
+	}{
+		"limits": {
+			input: `{"i8": -128, "u16": 65535, "u": 4294967295, "i64": -9223372036854775808}`,
+			want:  numbers{I8: -128, U16: 65535, U: math.MaxUint32, I64: math.MinInt64},
+		},
+		"integral floats": {
+			input: `{"i8": 12.0, "u16": 1e3, "arr": [1.0, 255.0]}`,
+			want:  numbers{I8: 12, U16: 1000, Arr: []uint8{1, 255}},
+		},
+		"float32 rounding": {
+			input: `{"f32": 0.1}`,
+			want:  numbers{F32: 0.1},
+		},
+	}
+
+	for name, test := range valid {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to numbers
+			un, _ := NewUnfolder(&to)
+			if err := json.ParseString(test.input, un); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, to)
+		})
+	}
+
+	invalid := map[string]struct {
+		input string
+		path  string
+	}{
+		"int8 overflow":      {`{"i8": 300}`, "i8"},
+		"int8 underflow":     {`{"i8": -129}`, "i8"},
+		"negative unsigned":  {`{"u": -1}`, "u"},
+		"uint16 overflow":    {`{"u16": 65536}`, "u16"},
+		"fraction":           {`{"i64": 1.5}`, "i64"},
+		"float overflow":     {`{"i64": 1e19}`, "i64"},
+		"float32 overflow":   {`{"f32": 1e39}`, "f32"},
+		"float32 precision":  {`{"f32": 16777217}`, "f32"},
+		"array element":      {`{"arr": [1, 256]}`, "arr[1]"},
+		"map value overflow": {`{"map": {"a": 1, "b": 40000}}`, "map.b"},
+	}
+
+	for name, test := range invalid {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to numbers
+			un, _ := NewUnfolder(&to)
+			err := json.ParseString(test.input, un)
+			if serr, ok := err.(*structform.SyntaxError); ok {
+				err = serr.Err
+			}
+
+			uerr, ok := err.(*UnfoldError)
+			if !ok {
+				t.Fatalf("expected unfold error, got: %v", err)
+			}
+			assert.Equal(t, test.path, uerr.Path)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var to numbers
+		un, _ := NewUnfolder(&to, UnfoldNumberChecks(false))
+		if err := json.ParseString(`{"i8": 300, "u16": 1.5}`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, numbers{I8: 44, U16: 1}, to)
+	})
+}
+
+func TestUnfoldStringTag(t *testing.T) {
+	type config struct {
+		Port    int      `struct:"port,string"`
+		Enabled bool     `struct:"enabled,string"`
+		Ratio   *float64 `struct:"ratio,string"`
+		Name    string   `struct:"name,string"`
+	}
+
+	t.Run("parse strings", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		input := `{"port": "9200", "enabled": "true", "ratio": "0.5", "name": "test"}`
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		ratio := 0.5
+		assert.Equal(t, config{Port: 9200, Enabled: true, Ratio: &ratio, Name: "test"}, to)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		if err := json.ParseString(`{"ratio": null}`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, to.Ratio)
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		type defaults struct {
+			Port    int     `struct:"port,string,default=42"`
+			Enabled *bool   `struct:"enabled,string,default=true"`
+			Ratio   float64 `struct:"ratio,string,default=0.5"`
+		}
+
+		var to defaults
+		un, _ := NewUnfolder(&to)
+		if err := json.ParseString(`{}`, un); err != nil {
+			t.Fatal(err)
+		}
+
+		enabled := true
+		assert.Equal(t, defaults{Port: 42, Enabled: &enabled, Ratio: 0.5}, to)
+
+		to = defaults{}
+		un, _ = NewUnfolder(&to)
+		if err := json.ParseString(`{"port": "1"}`, un); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, to.Port)
+	})
+
+	errors := map[string]struct {
+		input string
+		path  string
+	}{
+		"unquoted number": {`{"port": 9200}`, "port"},
+		"invalid number":  {`{"port": "abc"}`, "port"},
+		"invalid boolean": {`{"enabled": "yes"}`, "enabled"},
+		"overflow":        {`{"port": "1e30"}`, "port"},
+	}
+	for name, test := range errors {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var to config
+			un, _ := NewUnfolder(&to)
+			err := json.ParseString(test.input, un)
+			if serr, ok := err.(*structform.SyntaxError); ok {
+				err = serr.Err
+			}
+
+			uerr, ok := err.(*UnfoldError)
+			if !ok {
+				t.Fatalf("expected unfold error, got: %v", err)
+			}
+			assert.Equal(t, test.path, uerr.Path)
+		})
+	}
+}
+
+func TestUnfoldCoerce(t *testing.T) {
+	type config struct {
+		Port    int
+		Enabled bool
+		Ratio   float32
+		Name    string
+		Version string
+		Flag    string
+		Ports   []uint16
+		Labels  map[string]string
+	}
+
+	input := `{
+		"port": "9200", "enabled": "false", "ratio": "1.5",
+		"name": 1, "version": 1.25, "flag": true,
+		"ports": ["80", 443], "labels": {"a": 1, "b": "x"}
+	}`
+
+	t.Run("enabled", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to, UnfoldCoerce(true))
+		if err := json.ParseString(input, un); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, config{
+			Port:    9200,
+			Enabled: false,
+			Ratio:   1.5,
+			Name:    "1",
+			Version: "1.25",
+			Flag:    "true",
+			Ports:   []uint16{80, 443},
+			Labels:  map[string]string{"a": "1", "b": "x"},
+		}, to)
+	})
+
+	t.Run("range checks", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to, UnfoldCoerce(true))
+		err := json.ParseString(`{"ports": ["70000"]}`, un)
+		assert.Error(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var to config
+		un, _ := NewUnfolder(&to)
+		err := json.ParseString(input, un)
+		if serr, ok := err.(*structform.SyntaxError); ok {
+			err = serr.Err
+		}
+
+		uerr, ok := err.(*UnfoldError)
+		if !ok {
+			t.Fatalf("expected unfold error, got: %v", err)
+		}
+		assert.Equal(t, "port", uerr.Path)
+		assert.Equal(t, errUnsupported, uerr.Err)
+	})
+}
@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// unfolderReflText unfolds strings into types implementing
+// encoding.TextUnmarshaler.
+type unfolderReflText struct {
+	unfolderErrUnknown
+}
+
+var _singletonUnfolderReflText = &unfolderReflText{}
+
+// isTextUnmarshaler checks if the pointer type t implements
+// encoding.TextUnmarshaler and support for TextUnmarshaler is enabled.
+func isTextUnmarshaler(ctx *unfoldCtx, t reflect.Type) bool {
+	return ctx.opts.textMarshaler && t.Implements(tTextUnmarshaler)
+}
+
+func (u *unfolderReflText) initState(ctx *unfoldCtx, v reflect.Value) {
+	ctx.value.push(v)
+	ctx.unfolder.push(u)
+}
+
+func (u *unfolderReflText) cleanup(ctx *unfoldCtx) {
+	ctx.value.pop()
+	ctx.unfolder.pop()
+}
+
+func (u *unfolderReflText) OnNil(ctx *unfoldCtx) error {
+	v := ctx.value.current.Elem()
+	v.Set(reflect.Zero(v.Type()))
+	u.cleanup(ctx)
+	return nil
+}
+
+func (u *unfolderReflText) OnString(ctx *unfoldCtx, s string) error {
+	return u.OnStringRef(ctx, []byte(s))
+}
+
+func (u *unfolderReflText) OnStringRef(ctx *unfoldCtx, b []byte) error {
+	m := ctx.value.current.Interface().(encoding.TextUnmarshaler)
+	err := m.UnmarshalText(b)
+	u.cleanup(ctx)
+	return err
+}
+
+// unmarshalTextKey decodes an object key into a new value of type t.
+func unmarshalTextKey(t reflect.Type, key string) (reflect.Value, error) {
+	k := reflect.New(t)
+	if err := k.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+		return reflect.Value{}, err
+	}
+	return k.Elem(), nil
+}
+
+// buildReflMapTextKeys creates an unfolder for maps with non-string keys.
+// The key type must implement encoding.TextUnmarshaler.
+func buildReflMapTextKeys(ctx *unfoldCtx, t reflect.Type) (reflUnfolder, error) {
+	kt := t.Key()
+	if !isTextUnmarshaler(ctx, reflect.PtrTo(kt)) {
+		return nil, errMapRequiresStringKey
+	}
+
+	et := t.Elem()
+	elem := lookupReflUser(ctx, et)
+	if elem == nil {
+		var err error
+		elem, err = lookupReflUnfolder(ctx, reflect.PtrTo(et), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := newUnfolderReflMap(elem)
+	u.shared.keyType = kt
+	return u, nil
+}
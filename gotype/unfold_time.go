@@ -0,0 +1,191 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gotype
+
+import (
+	"math"
+	"time"
+	"unsafe"
+)
+
+type unfolderTime struct {
+	unfolderErrUnknown
+	enc    TimeEncoding
+	layout string
+}
+
+type unfolderDuration struct {
+	unfolderErrUnknown
+}
+
+var _singletonUnfolderDuration = &unfolderDuration{}
+
+func newUnfolderTime(enc TimeEncoding) *unfolderTime {
+	return &unfolderTime{enc: enc, layout: enc.getLayout()}
+}
+
+func (u *unfolderTime) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
+	ctx.unfolder.push(u)
+	ctx.ptr.push(ptr)
+}
+
+func (u *unfolderTime) cleanup(ctx *unfoldCtx) {
+	ctx.unfolder.pop()
+	ctx.ptr.pop()
+}
+
+func (u *unfolderTime) process(ctx *unfoldCtx, t time.Time) error {
+	*(*time.Time)(ctx.ptr.current) = t
+	u.cleanup(ctx)
+	return nil
+}
+
+func (u *unfolderTime) processEpoch(ctx *unfoldCtx, v int64) error {
+	if !u.enc.isEpoch() {
+		return errUnexpectedNum
+	}
+	return u.process(ctx, u.enc.fromEpoch(v))
+}
+
+func (u *unfolderTime) processEpochFloat(ctx *unfoldCtx, v float64) error {
+	if !u.enc.isEpoch() {
+		return errUnexpectedNum
+	}
+	return u.process(ctx, u.enc.fromEpochFloat(v))
+}
+
+func (u *unfolderTime) OnNil(ctx *unfoldCtx) error {
+	return u.process(ctx, time.Time{})
+}
+
+func (u *unfolderTime) OnString(ctx *unfoldCtx, s string) error {
+	t, err := time.Parse(u.layout, s)
+	if err != nil {
+		return err
+	}
+	return u.process(ctx, t)
+}
+
+func (u *unfolderTime) OnStringRef(ctx *unfoldCtx, b []byte) error {
+	return u.OnString(ctx, string(b))
+}
+
+func (u *unfolderTime) OnInt8(ctx *unfoldCtx, v int8) error   { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnInt16(ctx *unfoldCtx, v int16) error { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnInt32(ctx *unfoldCtx, v int32) error { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnInt64(ctx *unfoldCtx, v int64) error { return u.processEpoch(ctx, v) }
+func (u *unfolderTime) OnInt(ctx *unfoldCtx, v int) error     { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnByte(ctx *unfoldCtx, v byte) error   { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnUint8(ctx *unfoldCtx, v uint8) error { return u.processEpoch(ctx, int64(v)) }
+func (u *unfolderTime) OnUint16(ctx *unfoldCtx, v uint16) error {
+	return u.processEpoch(ctx, int64(v))
+}
+func (u *unfolderTime) OnUint32(ctx *unfoldCtx, v uint32) error {
+	return u.processEpoch(ctx, int64(v))
+}
+func (u *unfolderTime) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if v > math.MaxInt64 {
+		return errUnexpectedNum
+	}
+	return u.processEpoch(ctx, int64(v))
+}
+func (u *unfolderTime) OnUint(ctx *unfoldCtx, v uint) error { return u.OnUint64(ctx, uint64(v)) }
+func (u *unfolderTime) OnFloat32(ctx *unfoldCtx, v float32) error {
+	return u.processEpochFloat(ctx, float64(v))
+}
+func (u *unfolderTime) OnFloat64(ctx *unfoldCtx, v float64) error {
+	return u.processEpochFloat(ctx, v)
+}
+
+func (u *unfolderDuration) initState(ctx *unfoldCtx, ptr unsafe.Pointer) {
+	ctx.unfolder.push(u)
+	ctx.ptr.push(ptr)
+}
+
+func (u *unfolderDuration) cleanup(ctx *unfoldCtx) {
+	ctx.unfolder.pop()
+	ctx.ptr.pop()
+}
+
+func (u *unfolderDuration) process(ctx *unfoldCtx, d time.Duration) error {
+	*(*time.Duration)(ctx.ptr.current) = d
+	u.cleanup(ctx)
+	return nil
+}
+
+func (u *unfolderDuration) OnNil(ctx *unfoldCtx) error {
+	return u.process(ctx, 0)
+}
+
+func (u *unfolderDuration) OnString(ctx *unfoldCtx, s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	return u.process(ctx, d)
+}
+
+func (u *unfolderDuration) OnStringRef(ctx *unfoldCtx, b []byte) error {
+	return u.OnString(ctx, string(b))
+}
+
+func (u *unfolderDuration) OnInt8(ctx *unfoldCtx, v int8) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnInt16(ctx *unfoldCtx, v int16) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnInt32(ctx *unfoldCtx, v int32) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnInt64(ctx *unfoldCtx, v int64) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnInt(ctx *unfoldCtx, v int) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnByte(ctx *unfoldCtx, v byte) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnUint8(ctx *unfoldCtx, v uint8) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnUint16(ctx *unfoldCtx, v uint16) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnUint32(ctx *unfoldCtx, v uint32) error {
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if v > math.MaxInt64 {
+		return errUnexpectedNum
+	}
+	return u.process(ctx, time.Duration(v))
+}
+func (u *unfolderDuration) OnUint(ctx *unfoldCtx, v uint) error {
+	return u.OnUint64(ctx, uint64(v))
+}
+func (u *unfolderDuration) OnFloat32(ctx *unfoldCtx, v float32) error {
+	return u.OnFloat64(ctx, float64(v))
+}
+func (u *unfolderDuration) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(v, 64); err != nil {
+		return err
+	}
+	return u.process(ctx, time.Duration(v))
+}
@@ -92,6 +92,11 @@ type unfoldStateInitFn func(unsafe.Pointer) UnfoldState
 
 type unfoldExpanderInit struct{}
 
+// valueState starts unfolding into the target value on the first event.
+type valueState struct {
+	to interface{}
+}
+
 func makeUserUnfolder(fn reflect.Value) (target reflect.Type, unfolder reflUnfolder, err error) {
 	t := fn.Type()
 
@@ -246,6 +251,117 @@ func (*unfoldExpanderInit) initState(ctx *unfoldCtx, val reflect.Value) {
 	ctx.Push(st)
 }
 
+// ValueState creates an UnfoldState unfolding the next value into to, using
+// the unfolders and options of the active Unfolder. ValueState allows custom
+// UnfoldState implementations to delegate values they do not handle
+// themselves. The returned state must be pushed onto the stack before
+// passing the current event. If to is nil, the value is handled like the
+// value of an unknown struct field.
+func ValueState(to interface{}) UnfoldState {
+	return &valueState{to}
+}
+
+// start replaces the state with the unfolder for the target value.
+func (s *valueState) start(ctx UnfoldCtx) (*unfoldCtx, error) {
+	c, ok := ctx.(*unfoldCtx)
+	if !ok {
+		return nil, errInvalidState
+	}
+	c.unfolder.pop()
+
+	if s.to == nil {
+		switch c.opts.unknownFields {
+		case UnknownFieldFail:
+			return nil, errUnknownField
+		case UnknownFieldCollect:
+			c.unknownFields = append(c.unknownFields, c.pathString())
+		}
+
+		_singletonUnfoldIgnorePtr.initState(c, nil)
+		return c, nil
+	}
+
+	t := reflect.TypeOf(s.to)
+	if t.Kind() != reflect.Ptr {
+		return nil, errRequiresPointer
+	}
+
+	u, err := lookupReflUnfolder(c, t, true)
+	if err != nil {
+		return nil, err
+	}
+	u.initState(c, reflect.ValueOf(s.to))
+	return c, nil
+}
+
+func (s *valueState) OnNil(ctx UnfoldCtx) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnNil(c)
+}
+
+func (s *valueState) OnBool(ctx UnfoldCtx, b bool) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnBool(c, b)
+}
+
+func (s *valueState) OnString(ctx UnfoldCtx, str string) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnString(c, str)
+}
+
+func (s *valueState) OnInt(ctx UnfoldCtx, i int64) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnInt64(c, i)
+}
+
+func (s *valueState) OnUint(ctx UnfoldCtx, u uint64) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnUint64(c, u)
+}
+
+func (s *valueState) OnFloat(ctx UnfoldCtx, f float64) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnFloat64(c, f)
+}
+
+func (s *valueState) OnArrayStart(ctx UnfoldCtx, length int, bt structform.BaseType) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnArrayStart(c, length, bt)
+}
+
+func (s *valueState) OnObjectStart(ctx UnfoldCtx, length int, bt structform.BaseType) error {
+	c, err := s.start(ctx)
+	if err != nil {
+		return err
+	}
+	return c.unfolder.current.OnObjectStart(c, length, bt)
+}
+
+func (s *valueState) OnArrayFinished(ctx UnfoldCtx) error   { return errUnexpectedArrayEnd }
+func (s *valueState) OnObjectFinished(ctx UnfoldCtx) error  { return errUnexpectedObjectEnd }
+func (s *valueState) OnKey(ctx UnfoldCtx, key string) error { return errExpectedObjectValue }
+
 func (ctx *unfoldCtx) Done() {
 	ctx.unfolder.pop()
 }
@@ -308,7 +424,9 @@ func (u *stateUnfolder) OnObjectFinished(ctx *unfoldCtx) error {
 }
 func (u *stateUnfolder) OnKey(ctx *unfoldCtx, v string) error { return u.unfolder.OnKey(ctx, v) }
 func (u *stateUnfolder) OnKeyRef(ctx *unfoldCtx, v []byte) error {
-	return u.unfolder.OnKey(ctx, string(v))
+	key := string(v)
+	ctx.setPathKey(key)
+	return u.unfolder.OnKey(ctx, key)
 }
 func (u *stateUnfolder) OnChildObjectDone(ctx *unfoldCtx) error { return nil }
 
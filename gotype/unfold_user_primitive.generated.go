@@ -19,7 +19,9 @@
 package gotype
 
 import (
+	"math/bits"
 	"reflect"
+	"strconv"
 	"unsafe"
 
 	stunsafe "github.com/elastic/go-structform/internal/unsafe"
@@ -465,6 +467,13 @@ func (u *userUnfolderBool) OnNil(ctx *unfoldCtx) error {
 
 func (u *userUnfolderBool) OnBool(ctx *unfoldCtx, v bool) error { return u.process(ctx, v) }
 
+func (u *userUnfolderBool) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceBool(u, v)
+}
+func (u *userUnfolderBool) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceBool(u, bytes2Str(v))
+}
+
 func (u *userUnfolderString) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, "")
 }
@@ -474,11 +483,112 @@ func (u *userUnfolderString) OnStringRef(ctx *unfoldCtx, v []byte) error {
 	return u.OnString(ctx, string(v))
 }
 
+func (u *userUnfolderString) OnBool(ctx *unfoldCtx, v bool) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatBool(v))
+}
+
+func (u *userUnfolderString) OnByte(ctx *unfoldCtx, v byte) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnUint(ctx *unfoldCtx, v uint) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatUint(uint64(v), 10))
+}
+
+func (u *userUnfolderString) OnInt(ctx *unfoldCtx, v int) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *userUnfolderString) OnInt8(ctx *unfoldCtx, v int8) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *userUnfolderString) OnInt16(ctx *unfoldCtx, v int16) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *userUnfolderString) OnInt32(ctx *unfoldCtx, v int32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *userUnfolderString) OnInt64(ctx *unfoldCtx, v int64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatInt(int64(v), 10))
+}
+
+func (u *userUnfolderString) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+func (u *userUnfolderString) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if !ctx.opts.coerce {
+		return errUnsupported
+	}
+	return u.process(ctx, strconv.FormatFloat(float64(v), 'g', -1, 64))
+}
+
 func (u *userUnfolderUint) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderUint) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
@@ -487,49 +597,89 @@ func (u *userUnfolderUint) OnUint(ctx *unfoldCtx, v uint) error {
 }
 
 func (u *userUnfolderUint) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
 func (u *userUnfolderUint) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, uint(v))
 }
 
+func (u *userUnfolderUint) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderUint) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderUint8) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
@@ -539,6 +689,9 @@ func (u *userUnfolderUint8) OnByte(ctx *unfoldCtx, v byte) error {
 }
 
 func (u *userUnfolderUint8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
@@ -547,58 +700,104 @@ func (u *userUnfolderUint8) OnUint8(ctx *unfoldCtx, v uint8) error {
 }
 
 func (u *userUnfolderUint8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
 func (u *userUnfolderUint8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, uint8(v))
 }
 
+func (u *userUnfolderUint8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderUint8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderUint16) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderUint16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
@@ -607,58 +806,104 @@ func (u *userUnfolderUint16) OnUint16(ctx *unfoldCtx, v uint16) error {
 }
 
 func (u *userUnfolderUint16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
 func (u *userUnfolderUint16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, uint16(v))
 }
 
+func (u *userUnfolderUint16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderUint16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderUint32) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderUint32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
@@ -667,58 +912,104 @@ func (u *userUnfolderUint32) OnUint32(ctx *unfoldCtx, v uint32) error {
 }
 
 func (u *userUnfolderUint32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkUintFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
 func (u *userUnfolderUint32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, uint32(v))
 }
 
+func (u *userUnfolderUint32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderUint32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderUint64) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderUint64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkUintFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
@@ -727,58 +1018,104 @@ func (u *userUnfolderUint64) OnUint64(ctx *unfoldCtx, v uint64) error {
 }
 
 func (u *userUnfolderUint64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkUintFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
 func (u *userUnfolderUint64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkUintFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, uint64(v))
 }
 
+func (u *userUnfolderUint64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderUint64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderInt) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderInt) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
@@ -787,58 +1124,104 @@ func (u *userUnfolderInt) OnInt(ctx *unfoldCtx, v int) error {
 }
 
 func (u *userUnfolderInt) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
 func (u *userUnfolderInt) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), bits.UintSize); err != nil {
+		return err
+	}
 	return u.process(ctx, int(v))
 }
 
+func (u *userUnfolderInt) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderInt) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderInt8) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderInt8) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
@@ -847,58 +1230,104 @@ func (u *userUnfolderInt8) OnInt8(ctx *unfoldCtx, v int8) error {
 }
 
 func (u *userUnfolderInt8) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
 func (u *userUnfolderInt8) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 8); err != nil {
+		return err
+	}
 	return u.process(ctx, int8(v))
 }
 
+func (u *userUnfolderInt8) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderInt8) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderInt16) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderInt16) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
@@ -907,58 +1336,104 @@ func (u *userUnfolderInt16) OnInt16(ctx *unfoldCtx, v int16) error {
 }
 
 func (u *userUnfolderInt16) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
 func (u *userUnfolderInt16) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 16); err != nil {
+		return err
+	}
 	return u.process(ctx, int16(v))
 }
 
+func (u *userUnfolderInt16) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderInt16) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderInt32) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderInt32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
@@ -967,58 +1442,104 @@ func (u *userUnfolderInt32) OnInt32(ctx *unfoldCtx, v int32) error {
 }
 
 func (u *userUnfolderInt32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkIntFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
 func (u *userUnfolderInt32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, int32(v))
 }
 
+func (u *userUnfolderInt32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderInt32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderInt64) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderInt64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkIntFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkIntFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
@@ -1027,58 +1548,104 @@ func (u *userUnfolderInt64) OnInt64(ctx *unfoldCtx, v int64) error {
 }
 
 func (u *userUnfolderInt64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
 func (u *userUnfolderInt64) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkIntFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, int64(v))
 }
 
+func (u *userUnfolderInt64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderInt64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderFloat32) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderFloat32) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
 func (u *userUnfolderFloat32) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
@@ -1087,61 +1654,114 @@ func (u *userUnfolderFloat32) OnFloat32(ctx *unfoldCtx, v float32) error {
 }
 
 func (u *userUnfolderFloat32) OnFloat64(ctx *unfoldCtx, v float64) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 32); err != nil {
+		return err
+	}
 	return u.process(ctx, float32(v))
 }
 
+func (u *userUnfolderFloat32) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderFloat32) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
+
 func (u *userUnfolderFloat64) OnNil(ctx *unfoldCtx) error {
 	return u.process(ctx, 0)
 }
 
 func (u *userUnfolderFloat64) OnByte(ctx *unfoldCtx, v byte) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnUint(ctx *unfoldCtx, v uint) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnUint8(ctx *unfoldCtx, v uint8) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnUint16(ctx *unfoldCtx, v uint16) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnUint32(ctx *unfoldCtx, v uint32) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnUint64(ctx *unfoldCtx, v uint64) error {
+	if err := ctx.checkFloatFromUint(uint64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnInt(ctx *unfoldCtx, v int) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnInt8(ctx *unfoldCtx, v int8) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnInt16(ctx *unfoldCtx, v int16) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnInt32(ctx *unfoldCtx, v int32) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnInt64(ctx *unfoldCtx, v int64) error {
+	if err := ctx.checkFloatFromInt(int64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnFloat32(ctx *unfoldCtx, v float32) error {
+	if err := ctx.checkFloatFromFloat(float64(v), 64); err != nil {
+		return err
+	}
 	return u.process(ctx, float64(v))
 }
 
 func (u *userUnfolderFloat64) OnFloat64(ctx *unfoldCtx, v float64) error {
 	return u.process(ctx, float64(v))
 }
+
+func (u *userUnfolderFloat64) OnString(ctx *unfoldCtx, v string) error {
+	return ctx.coerceNumber(u, v)
+}
+func (u *userUnfolderFloat64) OnStringRef(ctx *unfoldCtx, v []byte) error {
+	return ctx.coerceNumber(u, bytes2Str(v))
+}
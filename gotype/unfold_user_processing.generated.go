@@ -117,103 +117,103 @@ func (u *unfolderUserProcessing) finalize(ctx *unfoldCtx) error {
 
 func (u *unfolderUserProcessing) OnNil(ctx *unfoldCtx) error {
 	u.beforeCall(ctx)
-	err := ctx.OnNil()
+	err := ctx.unfolder.current.OnNil(ctx)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnStringRef(ctx *unfoldCtx, v []byte) error {
 	u.beforeCall(ctx)
-	err := ctx.OnStringRef(v)
+	err := ctx.unfolder.current.OnStringRef(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnBool(ctx *unfoldCtx, v bool) error {
 	u.beforeCall(ctx)
-	err := ctx.OnBool(v)
+	err := ctx.unfolder.current.OnBool(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnString(ctx *unfoldCtx, v string) error {
 	u.beforeCall(ctx)
-	err := ctx.OnString(v)
+	err := ctx.unfolder.current.OnString(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnUint(ctx *unfoldCtx, v uint) error {
 	u.beforeCall(ctx)
-	err := ctx.OnUint(v)
+	err := ctx.unfolder.current.OnUint(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnUint8(ctx *unfoldCtx, v uint8) error {
 	u.beforeCall(ctx)
-	err := ctx.OnUint8(v)
+	err := ctx.unfolder.current.OnUint8(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnUint16(ctx *unfoldCtx, v uint16) error {
 	u.beforeCall(ctx)
-	err := ctx.OnUint16(v)
+	err := ctx.unfolder.current.OnUint16(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnUint32(ctx *unfoldCtx, v uint32) error {
 	u.beforeCall(ctx)
-	err := ctx.OnUint32(v)
+	err := ctx.unfolder.current.OnUint32(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnUint64(ctx *unfoldCtx, v uint64) error {
 	u.beforeCall(ctx)
-	err := ctx.OnUint64(v)
+	err := ctx.unfolder.current.OnUint64(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnInt(ctx *unfoldCtx, v int) error {
 	u.beforeCall(ctx)
-	err := ctx.OnInt(v)
+	err := ctx.unfolder.current.OnInt(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnInt8(ctx *unfoldCtx, v int8) error {
 	u.beforeCall(ctx)
-	err := ctx.OnInt8(v)
+	err := ctx.unfolder.current.OnInt8(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnInt16(ctx *unfoldCtx, v int16) error {
 	u.beforeCall(ctx)
-	err := ctx.OnInt16(v)
+	err := ctx.unfolder.current.OnInt16(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnInt32(ctx *unfoldCtx, v int32) error {
 	u.beforeCall(ctx)
-	err := ctx.OnInt32(v)
+	err := ctx.unfolder.current.OnInt32(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnInt64(ctx *unfoldCtx, v int64) error {
 	u.beforeCall(ctx)
-	err := ctx.OnInt64(v)
+	err := ctx.unfolder.current.OnInt64(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnFloat32(ctx *unfoldCtx, v float32) error {
 	u.beforeCall(ctx)
-	err := ctx.OnFloat32(v)
+	err := ctx.unfolder.current.OnFloat32(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnFloat64(ctx *unfoldCtx, v float64) error {
 	u.beforeCall(ctx)
-	err := ctx.OnFloat64(v)
+	err := ctx.unfolder.current.OnFloat64(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnByte(ctx *unfoldCtx, v byte) error {
 	u.beforeCall(ctx)
-	err := ctx.OnByte(v)
+	err := ctx.unfolder.current.OnByte(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
@@ -255,12 +255,12 @@ func (u *unfolderUserProcessing) OnChildObjectDone(ctx *unfoldCtx) error {
 
 func (u *unfolderUserProcessing) OnKey(ctx *unfoldCtx, v string) error {
 	u.beforeCall(ctx)
-	err := ctx.OnKey(v)
+	err := ctx.unfolder.current.OnKey(ctx, v)
 	return u.afterCall(ctx, err)
 }
 
 func (u *unfolderUserProcessing) OnKeyRef(ctx *unfoldCtx, v []byte) error {
 	u.beforeCall(ctx)
-	err := ctx.OnKeyRef(v)
+	err := ctx.unfolder.current.OnKeyRef(ctx, v)
 	return u.afterCall(ctx, err)
 }
@@ -50,6 +50,19 @@ func NewBytesDecoder(b []byte, vs structform.Visitor) *Decoder {
 	return dec
 }
 
+// SetAllowNonFinite configures the decoder to accept the non-standard
+// literals NaN, Infinity and -Infinity (as used by JSON5) for float values.
+func (dec *Decoder) SetAllowNonFinite(b bool) {
+	dec.p.SetAllowNonFinite(b)
+}
+
+// SetRelaxed configures the decoder to accept a lenient JSON dialect with
+// comments, trailing commas, single quoted strings, unquoted object keys and
+// hex numbers. See (*Parser).SetRelaxed.
+func (dec *Decoder) SetRelaxed(b bool) {
+	dec.p.SetRelaxed(b)
+}
+
 func (dec *Decoder) Next() error {
 	var (
 		n        int
@@ -25,6 +25,14 @@ var (
 	falseSymbol = []byte("false")
 	commaSymbol = []byte(",")
 
+	colonSpaceSymbol = []byte(": ")
+	newlineSymbol    = []byte("\n")
+
+	// quoted non-finite float symbols
+	nanSymbol    = []byte(`"NaN"`)
+	posInfSymbol = []byte(`"Infinity"`)
+	negInfSymbol = []byte(`"-Infinity"`)
+
 	invalidCharSym = []byte(`\ufffd`)
 )
 
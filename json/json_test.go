@@ -19,7 +19,9 @@ package json
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -129,3 +131,387 @@ func TestStringEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyPrint(t *testing.T) {
+	type testCase struct {
+		prefix, indent string
+		newline        bool
+		in             sftest.Recording
+		expected       string
+	}
+
+	doc := sftest.Recording(sftest.Obj(-1, structform.AnyType,
+		"a", sftest.IntRec{1},
+		"b", sftest.Arr(-1, structform.AnyType,
+			sftest.BoolRec{true},
+			sftest.Obj(-1, structform.AnyType),
+			sftest.Arr(-1, structform.AnyType),
+		),
+		"c", sftest.StringRec{"x"},
+	))
+
+	cases := map[string]testCase{
+		"compact": testCase{
+			in:       doc,
+			expected: `{"a":1,"b":[true,{},[]],"c":"x"}`,
+		},
+		"indent": testCase{
+			indent: "  ",
+			in:     doc,
+			expected: "{\n" +
+				"  \"a\": 1,\n" +
+				"  \"b\": [\n" +
+				"    true,\n" +
+				"    {},\n" +
+				"    []\n" +
+				"  ],\n" +
+				"  \"c\": \"x\"\n" +
+				"}",
+		},
+		"prefix and indent": testCase{
+			prefix:   "> ",
+			indent:   "\t",
+			in:       sftest.Recording(sftest.Arr(1, structform.AnyType, sftest.NilRec{})),
+			expected: "[\n> \tnull\n> ]",
+		},
+		"newline after documents": testCase{
+			newline: true,
+			in: append(
+				sftest.Recording{sftest.IntRec{1}, sftest.StringRec{"a"}},
+				sftest.Obj(1, structform.AnyType, "k", sftest.Arr(0, structform.AnyType))...,
+			),
+			expected: "1\n\"a\"\n{\"k\":[]}\n",
+		},
+		"indent with newline": testCase{
+			indent:   " ",
+			newline:  true,
+			in:       append(doc[:0:0], sftest.Obj(1, structform.AnyType, "k", sftest.Float64Rec{1.5})...),
+			expected: "{\n \"k\": 1.5\n}\n",
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			visitor := NewVisitor(buf)
+			visitor.SetIndent(test.prefix, test.indent)
+			visitor.SetNewline(test.newline)
+			if err := test.in.Replay(visitor); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestNonFinitePolicy(t *testing.T) {
+	in := sftest.Recording(sftest.Arr(3, structform.AnyType,
+		sftest.Float64Rec{math.NaN()},
+		sftest.Float32Rec{float32(math.Inf(1))},
+		sftest.Float64Rec{math.Inf(-1)},
+	))
+
+	cases := map[string]struct {
+		policy   NonFinitePolicy
+		expected string
+	}{
+		"null":    {NonFiniteNull, `[null,null,null]`},
+		"string":  {NonFiniteString, `["NaN","Infinity","-Infinity"]`},
+		"literal": {NonFiniteLiteral, `[NaN,Infinity,-Infinity]`},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			visitor := NewVisitor(buf)
+			visitor.SetNonFinitePolicy(test.policy)
+			if err := in.Replay(visitor); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		visitor := NewVisitor(&bytes.Buffer{})
+		assert.Error(t, in.Replay(visitor))
+	})
+}
+
+func TestParseNonFinite(t *testing.T) {
+	in := `[NaN, Infinity, -Infinity, +Infinity, 1]`
+
+	check := func(t *testing.T, rec sftest.Recording) {
+		if !assert.Len(t, rec, 7) {
+			return
+		}
+		assert.True(t, math.IsNaN(rec[1].(sftest.Float64Rec).Value))
+		assert.Equal(t, sftest.Float64Rec{math.Inf(1)}, rec[2])
+		assert.Equal(t, sftest.Float64Rec{math.Inf(-1)}, rec[3])
+		assert.Equal(t, sftest.Float64Rec{math.Inf(1)}, rec[4])
+		assert.Equal(t, sftest.Int64Rec{1}, rec[5])
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetAllowNonFinite(true)
+		if err := p.ParseString(in); err != nil {
+			t.Fatal(err)
+		}
+		check(t, rec)
+	})
+
+	t.Run("feed bytes", func(t *testing.T) {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetAllowNonFinite(true)
+		for i := range in {
+			if err := p.feed([]byte{in[i]}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := p.finalize(); err != nil {
+			t.Fatal(err)
+		}
+		check(t, rec)
+	})
+
+	t.Run("decoder", func(t *testing.T) {
+		var rec sftest.Recording
+		dec := NewBytesDecoder([]byte(in), &rec)
+		dec.SetAllowNonFinite(true)
+		if err := dec.Next(); err != nil {
+			t.Fatal(err)
+		}
+		check(t, rec)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		for _, in := range []string{"NaN", "Infinity", "-Infinity"} {
+			var rec sftest.Recording
+			assert.Error(t, ParseString(in, &rec), in)
+		}
+	})
+}
+
+func TestParseRelaxed(t *testing.T) {
+	in := `// configuration
+	{
+		/* block
+		 * comment */
+		hosts: ['localhost:9200', "remote",], // trailing comma
+		'max_$retries': 0x1F,
+		neg: -0X10,
+		quote: 'say "hi" \'there\'',
+		n: 1// comment directly after number
+		, nested: {a: 1, b_2: 2.5,},
+	}`
+
+	expected := sftest.Recording{
+		sftest.ObjectStartRec{-1, structform.AnyType},
+		sftest.ObjectKeyRec{"hosts"},
+		sftest.ArrayStartRec{-1, structform.AnyType},
+		sftest.StringRec{"localhost:9200"},
+		sftest.StringRec{"remote"},
+		sftest.ArrayFinishRec{},
+		sftest.ObjectKeyRec{"max_$retries"},
+		sftest.Int64Rec{31},
+		sftest.ObjectKeyRec{"neg"},
+		sftest.Int64Rec{-16},
+		sftest.ObjectKeyRec{"quote"},
+		sftest.StringRec{`say "hi" 'there'`},
+		sftest.ObjectKeyRec{"n"},
+		sftest.Int64Rec{1},
+		sftest.ObjectKeyRec{"nested"},
+		sftest.ObjectStartRec{-1, structform.AnyType},
+		sftest.ObjectKeyRec{"a"},
+		sftest.Int64Rec{1},
+		sftest.ObjectKeyRec{"b_2"},
+		sftest.Float64Rec{2.5},
+		sftest.ObjectFinishRec{},
+		sftest.ObjectFinishRec{},
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetRelaxed(true)
+		if err := p.ParseString(in); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, rec)
+	})
+
+	t.Run("feed bytes", func(t *testing.T) {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetRelaxed(true)
+		for i := range in {
+			if err := p.feed([]byte{in[i]}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := p.finalize(); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, rec)
+	})
+
+	t.Run("decoder", func(t *testing.T) {
+		var rec sftest.Recording
+		dec := NewDecoder(bytes.NewReader([]byte(in)), 3, &rec)
+		dec.SetRelaxed(true)
+		if err := dec.Next(); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, rec)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		for _, in := range []string{
+			`[1,]`,
+			`{"a": 1,}`,
+			`'str'`,
+			`{a: 1}`,
+			`{'a': 1}`,
+			`0x10`,
+			`/* comment */ 1`,
+		} {
+			var rec sftest.Recording
+			assert.Error(t, ParseString(in, &rec), in)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, in := range []string{
+			`[1 / 2]`,
+			`/* unterminated`,
+			`[,]`,
+			`{,}`,
+			`{1a: 1}`,
+			`'unterminated"`,
+		} {
+			var rec sftest.Recording
+			p := NewParser(&rec)
+			p.SetRelaxed(true)
+			assert.Error(t, p.ParseString(in), in)
+		}
+	})
+}
+
+func TestParseRelaxedHexLimits(t *testing.T) {
+	tests := map[string]sftest.Recording{
+		`0x7FFFFFFFFFFFFFFF`:  {sftest.Int64Rec{math.MaxInt64}},
+		`0x8000000000000000`:  {sftest.Uint64Rec{1 << 63}},
+		`0xFFFFFFFFFFFFFFFF`:  {sftest.Uint64Rec{math.MaxUint64}},
+		`-0x8000000000000000`: {sftest.Int64Rec{math.MinInt64}},
+		`-0x7FFFFFFFFFFFFFFF`: {sftest.Int64Rec{-math.MaxInt64}},
+	}
+
+	for in, expected := range tests {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetRelaxed(true)
+		if assert.NoError(t, p.ParseString(in), in) {
+			assert.Equal(t, expected, rec, in)
+		}
+	}
+
+	for _, in := range []string{`-0x8000000000000001`, `-0xFFFFFFFFFFFFFFFF`, `0x10000000000000000`} {
+		var rec sftest.Recording
+		p := NewParser(&rec)
+		p.SetRelaxed(true)
+		assert.Error(t, p.ParseString(in), in)
+	}
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	cases := map[string]struct {
+		in           string
+		offset       int64
+		line, column int
+		path         string
+	}{
+		"top-level": {
+			in:     `x`,
+			offset: 0, line: 1, column: 1,
+		},
+		"missing colon": {
+			in:     "{\n  \"a\": {\n    \"b\" 1}}",
+			offset: 19, line: 3, column: 9,
+			path: "a.b",
+		},
+		"array element": {
+			in:     `{"a": [1, {"b": [true, fals]}]}`,
+			offset: 24, line: 1, column: 25,
+			path: "a[1].b[1]",
+		},
+		"next key": {
+			in:     `{"a": 1, 2}`,
+			offset: 9, line: 1, column: 10,
+		},
+		"incomplete": {
+			in:     "[\n[1,\n",
+			offset: 6, line: 3, column: 1,
+			path: "[0][1]",
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var rec sftest.Recording
+			err := ParseString(test.in, &rec)
+
+			serr, ok := err.(*structform.SyntaxError)
+			if !ok {
+				t.Fatalf("expected syntax error, got: %v", err)
+			}
+			assert.Equal(t, test.offset, serr.Offset)
+			assert.Equal(t, test.line, serr.Line)
+			assert.Equal(t, test.column, serr.Column)
+			assert.Equal(t, test.path, serr.Path)
+		})
+	}
+
+	t.Run("decoder", func(t *testing.T) {
+		var rec sftest.Recording
+		dec := NewBytesDecoder([]byte("{\"a\": 1}\n{\"a\": 2}\n{\"a\": x}\n"), &rec)
+		for i := 0; i < 2; i++ {
+			if err := dec.Next(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := dec.Next()
+		serr, ok := err.(*structform.SyntaxError)
+		if !ok {
+			t.Fatalf("expected syntax error, got: %v", err)
+		}
+		assert.Equal(t, errUnknownChar, serr.Err)
+		assert.Equal(t, int64(24), serr.Offset)
+		assert.Equal(t, 3, serr.Line)
+		assert.Equal(t, 7, serr.Column)
+		assert.Equal(t, "a", serr.Path)
+		assert.Equal(t, "parse error at line 3, column 7 (offset 24) in 'a': unknown character", err.Error())
+	})
+
+	t.Run("visitor error", func(t *testing.T) {
+		var rec sftest.Recording
+		errVisitor := errors.New("visitor failed")
+		err := ParseString(`{"a": [1]}`, &failingVisitor{Visitor: &rec, err: errVisitor})
+		assert.Equal(t, errVisitor, err)
+	})
+}
+
+// failingVisitor fails on the first integer.
+type failingVisitor struct {
+	structform.Visitor
+	err error
+}
+
+func (v *failingVisitor) OnInt64(int64) error { return v.err }
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package jsontype combines the JSON parser and visitor with gotype, for
+// encoding and decoding go values of a known type.
+//
+// The helpers live in a separate package, as the gotype tests depend on the
+// json package.
+package jsontype
+
+import (
+	"bytes"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/gotype"
+	"github.com/elastic/go-structform/json"
+)
+
+// Marshal encodes v as JSON.
+func Marshal[T any](v T, opts ...gotype.FoldOption) ([]byte, error) {
+	var buf bytes.Buffer
+	it, err := gotype.NewTypedIterator[T](json.NewVisitor(&buf), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := it.Fold(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the JSON document in b into a new value of type T.
+func Unmarshal[T any](b []byte, opts ...gotype.UnfoldOption) (T, error) {
+	return gotype.UnfoldAs[T](func(vs structform.Visitor) error {
+		return json.Parse(b, vs)
+	}, opts...)
+}
+
+// UnmarshalString decodes the JSON document in str into a new value of type
+// T.
+func UnmarshalString[T any](str string, opts ...gotype.UnfoldOption) (T, error) {
+	return gotype.UnfoldAs[T](func(vs structform.Visitor) error {
+		return json.ParseString(str, vs)
+	}, opts...)
+}
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package jsontype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-structform/gotype"
+)
+
+type testConfig struct {
+	Name    string            `struct:"name"`
+	Hosts   []string          `struct:"hosts"`
+	Enabled bool              `struct:"enabled"`
+	Labels  map[string]string `struct:"labels,omitempty"`
+}
+
+func TestRoundtrip(t *testing.T) {
+	in := testConfig{Name: "test", Hosts: []string{"a", "b"}, Enabled: true}
+
+	b, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"name":"test","hosts":["a","b"],"enabled":true}`, string(b))
+
+	out, err := Unmarshal[testConfig](b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, in, out)
+	}
+}
+
+func TestUnmarshalString(t *testing.T) {
+	hosts, err := UnmarshalString[[]string](`["a", "b"]`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"a", "b"}, hosts)
+	}
+
+	_, err = UnmarshalString[testConfig](`{"name": "test", "port": 9200}`,
+		gotype.UnfoldUnknownFields(gotype.UnknownFieldFail))
+	assert.Error(t, err)
+
+	_, err = UnmarshalString[testConfig](`{"name": `)
+	assert.Error(t, err)
+}
+
+func TestMarshalOptions(t *testing.T) {
+	type point struct {
+		FieldX int
+	}
+
+	b, err := Marshal(point{1}, gotype.FoldNaming(gotype.NameSnakeCase))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"field_x":1}`, string(b))
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"math"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf16"
 	"unicode/utf8"
@@ -52,31 +53,77 @@ type Parser struct {
 	isDouble bool
 
 	required int
+
+	// accept the non-standard literals NaN, Infinity and -Infinity
+	allowNonFinite bool
+
+	// relaxed mode: comments, trailing commas, single quoted strings,
+	// unquoted keys and hex numbers
+	relaxed bool
+	comment commentState
+	quote   byte
+
+	// input location of the next byte to be processed
+	offset    int64
+	line      int   // number of newlines seen
+	lineStart int64 // offset of the first byte in the current line
+
+	// document path for error reporting
+	path    []pathElem
+	pathBuf []byte // object keys referenced by path
+}
+
+// pathElem describes an active array or object. For objects the current key
+// is stored in pathBuf[start:end].
+type pathElem struct {
+	idx        int // array index, -1 for objects
+	start, end int
 }
 
 var (
-	errFailing               = errors.New("JSON parser failed")
-	errIncomplete            = errors.New("Incomplete JSON input")
-	errUnknownChar           = errors.New("unknown character")
-	errQuoteMissing          = errors.New("missing closing quote")
-	errExpectColon           = errors.New("expected ':' after map key")
-	errUnexpectedDictClose   = errors.New("unexpected '}'")
-	errUnexpectedArrClose    = errors.New("unexpected ']'")
-	errExpectedDigit         = errors.New("expected a digit")
-	errExpectedObject        = errors.New("expected JSON object")
-	errExpectedArray         = errors.New("expected JSON array")
-	errExpectedFieldName     = errors.New("expected JSON object field name")
-	errExpectedInteger       = errors.New("expected integer value")
-	errExpectedNull          = errors.New("expected null value")
-	errExpectedFalse         = errors.New("expected false value")
-	errExpectedTrue          = errors.New("expected true value")
-	errExpectedArrayField    = errors.New("expected ']' or ','")
-	errUnquoteInEscape       = errors.New("incomplete escape at end of string")
-	errUnquoteInvalidChar    = errors.New("invalid character found in string")
-	errUnquoteInvalidUnicode = errors.New("unicode escape is no hex number")
-	errUnquoteUnknownEscape  = errors.New("unknown escape sequence")
+	errFailing               = newParseError("JSON parser failed")
+	errIncomplete            = newParseError("Incomplete JSON input")
+	errUnknownChar           = newParseError("unknown character")
+	errQuoteMissing          = newParseError("missing closing quote")
+	errExpectColon           = newParseError("expected ':' after map key")
+	errUnexpectedDictClose   = newParseError("unexpected '}'")
+	errUnexpectedArrClose    = newParseError("unexpected ']'")
+	errExpectedDigit         = newParseError("expected a digit")
+	errExpectedObject        = newParseError("expected JSON object")
+	errExpectedArray         = newParseError("expected JSON array")
+	errExpectedFieldName     = newParseError("expected JSON object field name")
+	errExpectedInteger       = newParseError("expected integer value")
+	errExpectedNull          = newParseError("expected null value")
+	errExpectedFalse         = newParseError("expected false value")
+	errExpectedTrue          = newParseError("expected true value")
+	errExpectedNaN           = newParseError("expected NaN value")
+	errExpectedInfinity      = newParseError("expected Infinity value")
+	errExpectedArrayField    = newParseError("expected ']' or ','")
+	errExpectedComment       = newParseError("expected '/' or '*' after '/'")
+	errUnquoteInEscape       = newParseError("incomplete escape at end of string")
+	errUnquoteInvalidChar    = newParseError("invalid character found in string")
+	errUnquoteInvalidUnicode = newParseError("unicode escape is no hex number")
+	errUnquoteUnknownEscape  = newParseError("unknown escape sequence")
 )
 
+// parseError marks errors detected by the parser. Only parse errors are
+// reported as structform.SyntaxError, errors returned by the visitor are
+// passed through unchanged.
+type parseError struct {
+	err error
+}
+
+func newParseError(msg string) error {
+	return &parseError{errors.New(msg)}
+}
+
+func parseErrorf(format string, args ...interface{}) error {
+	return &parseError{fmt.Errorf(format, args...)}
+}
+
+func (e *parseError) Error() string { return e.err.Error() }
+func (e *parseError) Unwrap() error { return e.err }
+
 type state uint8
 
 //go:generate stringer -type=state
@@ -100,6 +147,20 @@ const (
 	falseState
 	stringState
 	numberState
+	nanState
+	infinityState
+
+	dictFieldIdentState
+)
+
+type commentState uint8
+
+const (
+	noComment       commentState = iota
+	commentStart                 // read '/'
+	lineComment                  // in '//' comment
+	blockComment                 // in '/* */' comment
+	blockCommentEnd              // read '*' in block comment
 )
 
 func ParseReader(in io.Reader, vs structform.Visitor) (int64, error) {
@@ -135,10 +196,31 @@ func (p *Parser) init(vs structform.Visitor) {
 	p.literalBuffer = p.literalBuffer0[:0]
 }
 
+// SetAllowNonFinite configures the parser to accept the non-standard
+// literals NaN, Infinity and -Infinity (as used by JSON5) for float values.
+func (p *Parser) SetAllowNonFinite(b bool) {
+	p.allowNonFinite = b
+}
+
+// SetRelaxed configures the parser to accept a lenient JSON dialect
+// (similar to JSON5), supporting:
+//   - '//' line comments and '/* */' block comments
+//   - trailing commas in arrays and objects
+//   - single quoted strings
+//   - unquoted object keys made of letters, digits, '_' and '$'
+//   - hexadecimal integers like 0x1F
+func (p *Parser) SetRelaxed(b bool) {
+	p.relaxed = b
+}
+
 func (p *Parser) Parse(b []byte) error {
 	p.states = p.states[:0]
 	p.literalBuffer = p.literalBuffer[:0]
 	p.currentState = startState
+	p.comment = noComment
+	p.offset, p.line, p.lineStart = 0, 0, 0
+	p.path = p.path[:0]
+	p.pathBuf = p.pathBuf[:0]
 
 	p.err = p.feed(b)
 	if p.err == nil {
@@ -177,13 +259,15 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 		err      error
 		reported bool
 		orig     = b
+		last     []byte
 	)
 
-	for !reported && len(b) > 0 {
+	for err == nil && !reported && len(b) > 0 {
+		last = b
 		switch p.currentState {
 		case failedState:
 			if p.err == nil {
-				p.err = errors.New("invalid parser state")
+				p.err = newParseError("invalid parser state")
 			}
 			return 0, false, p.err
 		case startState:
@@ -198,13 +282,17 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 		case dictFieldState:
 			b, err = p.stepDictKey(b)
 
+		case dictFieldIdentState:
+			b, err = p.stepDictIdent(b)
+
 		case dictFieldValueSep:
-			if b = trimLeft(b); len(b) > 0 {
+			if b, err = p.skipSpace(b); len(b) > 0 {
 				if b[0] != ':' {
 					err = errExpectColon
+				} else {
+					b = b[1:]
+					p.currentState = dictFieldValue
 				}
-				b = b[1:]
-				p.currentState = dictFieldValue
 			}
 
 		case dictFieldValue:
@@ -237,6 +325,12 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 		case numberState:
 			b, reported, err = p.stepNumber(b)
 
+		case nanState:
+			b, reported, err = p.stepNaN(b)
+
+		case infinityState:
+			b, reported, err = p.stepInfinity(b)
+
 		default:
 			return 0, false, errFailing
 		}
@@ -244,21 +338,103 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 		reported = reported && len(p.states) == 0
 	}
 
+	if err != nil {
+		// Most steps return the remaining input on error. If not, report the
+		// error at the beginning of the failing step.
+		if b == nil {
+			b = last
+		}
+		consumed := len(orig) - len(b)
+		return consumed, false, p.syntaxError(err, orig[:consumed])
+	}
+
 	consumed := len(orig) - len(b)
-	return consumed, reported, err
+	p.advance(orig[:consumed])
+	return consumed, reported, nil
+}
+
+// advance updates the input location after b has been processed.
+func (p *Parser) advance(b []byte) {
+	if n := bytes.Count(b, newlineSymbol); n > 0 {
+		p.line += n
+		p.lineStart = p.offset + int64(bytes.LastIndexByte(b, '\n')) + 1
+	}
+	p.offset += int64(len(b))
+}
+
+// syntaxError annotates parse errors with the current document path and the
+// input location after processing b. Errors returned by the visitor are
+// passed through unchanged.
+func (p *Parser) syntaxError(err error, b []byte) error {
+	p.advance(b)
+	if _, ok := err.(*parseError); !ok {
+		return err
+	}
+	return &structform.SyntaxError{
+		Err:    err,
+		Offset: p.offset,
+		Line:   p.line + 1,
+		Column: int(p.offset-p.lineStart) + 1,
+		Path:   p.pathString(),
+	}
+}
+
+func (p *Parser) pathString() string {
+	var sb strings.Builder
+	for _, elem := range p.path {
+		if elem.idx >= 0 {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(elem.idx))
+			sb.WriteByte(']')
+			continue
+		}
+
+		if elem.start == elem.end {
+			break // no key read yet
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.Write(p.pathBuf[elem.start:elem.end])
+	}
+	return sb.String()
+}
+
+func (p *Parser) pushPath(idx int) {
+	n := len(p.pathBuf)
+	p.path = append(p.path, pathElem{idx: idx, start: n, end: n})
+}
+
+func (p *Parser) popPath() {
+	if last := len(p.path) - 1; last >= 0 {
+		p.pathBuf = p.pathBuf[:p.path[last].start]
+		p.path = p.path[:last]
+	}
+}
+
+func (p *Parser) setPathKey(key []byte) {
+	if last := len(p.path) - 1; last >= 0 {
+		elem := &p.path[last]
+		p.pathBuf = append(p.pathBuf[:elem.start], key...)
+		elem.end = len(p.pathBuf)
+	}
 }
 
 func (p *Parser) finalize() error {
+	if p.comment != noComment && p.comment != lineComment {
+		return p.syntaxError(errIncomplete, nil)
+	}
+
 	if p.currentState == numberState {
 		err := p.reportNumber(p.literalBuffer, p.isDouble)
 		if err != nil {
-			return err
+			return p.syntaxError(err, nil)
 		}
 		p.popState()
 	}
 
 	if len(p.states) > 0 && p.currentState != startState {
-		return errIncomplete
+		return p.syntaxError(errIncomplete, nil)
 	}
 
 	return nil
@@ -286,9 +462,9 @@ func (p *Parser) stepStart(b []byte) ([]byte, bool, error) {
 }
 
 func (p *Parser) stepValue(b []byte, retState state) ([]byte, bool, error) {
-	b = trimLeft(b)
-	if len(b) == 0 {
-		return b, false, nil
+	b, err := p.skipSpace(b)
+	if err != nil || len(b) == 0 {
+		return b, false, err
 	}
 
 	p.currentState = retState
@@ -296,10 +472,12 @@ func (p *Parser) stepValue(b []byte, retState state) ([]byte, bool, error) {
 	switch c {
 	case '{': // start dictionary
 		p.pushState(dictState)
+		p.pushPath(-1)
 		return b[1:], false, p.visitor.OnObjectStart(-1, structform.AnyType)
 
 	case '[': // start array
 		p.pushState(arrState)
+		p.pushPath(0)
 		return b[1:], false, p.visitor.OnArrayStart(-1, structform.AnyType)
 
 	case 'n': // parse "null"
@@ -318,10 +496,29 @@ func (p *Parser) stepValue(b []byte, retState state) ([]byte, bool, error) {
 		return p.stepTRUE(b[1:])
 
 	case '"': // parse string
-		p.literalBuffer = p.literalBuffer[:0]
-		p.pushState(stringState)
-		p.inEscape = false
-		return p.stepString(b[:])
+		return p.startString(b)
+
+	case '\'':
+		if !p.relaxed {
+			return b, false, errUnknownChar
+		}
+		return p.startString(b)
+
+	case 'N': // parse "NaN"
+		if !p.allowNonFinite {
+			return b, false, errUnknownChar
+		}
+		p.pushState(nanState)
+		p.required = 2
+		return p.stepNaN(b[1:])
+
+	case 'I': // parse "Infinity"
+		if !p.allowNonFinite {
+			return b, false, errUnknownChar
+		}
+		p.pushState(infinityState)
+		p.required = 7
+		return p.stepInfinity(b[1:])
 
 	default:
 		// parse number?
@@ -339,26 +536,48 @@ func (p *Parser) stepValue(b []byte, retState state) ([]byte, bool, error) {
 	}
 }
 
+func (p *Parser) startString(b []byte) ([]byte, bool, error) {
+	p.literalBuffer = p.literalBuffer[:0]
+	p.pushState(stringState)
+	p.inEscape = false
+	p.quote = b[0]
+	return p.stepString(b[:])
+}
+
 func (p *Parser) stepDict(b []byte, allowEnd bool) ([]byte, bool, error) {
-	b = trimLeft(b)
-	if len(b) == 0 {
-		return b, false, nil
+	b, err := p.skipSpace(b)
+	if err != nil || len(b) == 0 {
+		return b, false, err
 	}
 
 	c := b[0]
 	switch c {
 	case '}':
-		if !allowEnd {
-			return nil, false, errUnexpectedDictClose
+		if !allowEnd && !p.relaxed {
+			return b, false, errUnexpectedDictClose
 		}
 		return p.endDict(b)
 
 	case '"':
 		p.currentState = dictFieldState
+		p.quote = c
+		return b, false, nil
+
+	case '\'':
+		if !p.relaxed {
+			return b, false, errExpectedFieldName
+		}
+		p.currentState = dictFieldState
+		p.quote = c
 		return b, false, nil
 
 	default:
-		return nil, false, errExpectedFieldName
+		if !p.relaxed || !isIdentStart(c) {
+			return b, false, errExpectedFieldName
+		}
+		p.literalBuffer = p.literalBuffer[:0]
+		p.currentState = dictFieldIdentState
+		return b, false, nil
 	}
 }
 
@@ -366,6 +585,7 @@ func (p *Parser) stepDictKey(b []byte) ([]byte, error) {
 	ref, allocated, done, b, err := p.doString(b)
 	if done && err == nil {
 		p.currentState = dictFieldValueSep
+		p.setPathKey(ref)
 
 		if !allocated {
 			err = p.strVisitor.OnKeyRef(ref)
@@ -376,10 +596,36 @@ func (p *Parser) stepDictKey(b []byte) ([]byte, error) {
 	return b, err
 }
 
+func (p *Parser) stepDictIdent(b []byte) ([]byte, error) {
+	stop := -1
+	for i, c := range b {
+		if !isIdentChar(c) {
+			stop = i
+			break
+		}
+	}
+
+	if stop < 0 {
+		p.literalBuffer = append(p.literalBuffer, b...)
+		return nil, nil
+	}
+
+	rest := b[stop:]
+	b = b[:stop]
+	if len(p.literalBuffer) > 0 {
+		b = append(p.literalBuffer, b...)
+		p.literalBuffer = b[:0] // reset buffer
+	}
+
+	p.currentState = dictFieldValueSep
+	p.setPathKey(b)
+	return rest, p.strVisitor.OnKeyRef(b)
+}
+
 func (p *Parser) stepDictValueEnd(b []byte) ([]byte, bool, error) {
-	b = trimLeft(b)
-	if len(b) == 0 {
-		return b, false, nil
+	b, err := p.skipSpace(b)
+	if err != nil || len(b) == 0 {
+		return b, false, err
 	}
 
 	c := b[0]
@@ -388,28 +634,30 @@ func (p *Parser) stepDictValueEnd(b []byte) ([]byte, bool, error) {
 		return p.endDict(b)
 	case ',':
 		p.currentState = dictNextFieldState
+		p.setPathKey(nil)
 		return b[1:], false, nil
 	default:
-		return nil, false, errUnknownChar
+		return b, false, errUnknownChar
 	}
 }
 
 func (p *Parser) endDict(b []byte) ([]byte, bool, error) {
 	p.popState()
+	p.popPath()
 	return b[1:], true, p.visitor.OnObjectFinished()
 }
 
 func (p *Parser) stepArray(b []byte, allowEnd bool) ([]byte, bool, error) {
-	b = trimLeft(b)
-	if len(b) == 0 {
-		return b, false, nil
+	b, err := p.skipSpace(b)
+	if err != nil || len(b) == 0 {
+		return b, false, err
 	}
 
 	c := b[0]
 	switch c {
 	case ']':
 		if !allowEnd {
-			return nil, false, errUnexpectedArrClose
+			return b, false, errUnexpectedArrClose
 		}
 		return p.endArray(b)
 	}
@@ -419,9 +667,9 @@ func (p *Parser) stepArray(b []byte, allowEnd bool) ([]byte, bool, error) {
 }
 
 func (p *Parser) stepArrValueEnd(b []byte) ([]byte, bool, error) {
-	b = trimLeft(b)
-	if len(b) == 0 {
-		return b, false, nil
+	b, err := p.skipSpace(b)
+	if err != nil || len(b) == 0 {
+		return b, false, err
 	}
 
 	c := b[0]
@@ -429,15 +677,22 @@ func (p *Parser) stepArrValueEnd(b []byte) ([]byte, bool, error) {
 	case ']':
 		return p.endArray(b)
 	case ',':
-		p.currentState = arrStateValue
+		p.path[len(p.path)-1].idx++
+		if p.relaxed {
+			// allow trailing comma
+			p.currentState = arrState
+		} else {
+			p.currentState = arrStateValue
+		}
 		return b[1:], false, nil
 	default:
-		return nil, false, errUnknownChar
+		return b, false, errUnknownChar
 	}
 }
 
 func (p *Parser) endArray(b []byte) ([]byte, bool, error) {
 	p.popState()
+	p.popPath()
 	return b[1:], true, p.visitor.OnArrayFinished()
 }
 
@@ -474,7 +729,7 @@ func (p *Parser) doString(b []byte) ([]byte, bool, bool, []byte, error) {
 			continue
 		}
 
-		if c == '"' {
+		if c == p.quote {
 			done = true
 			stop = i + delta
 			break
@@ -517,7 +772,7 @@ func (p *Parser) unquote(in []byte) ([]byte, bool, error) {
 	i := 0
 	for i < len(in) {
 		c := in[i]
-		if c == '\\' || c == '"' || c < ' ' {
+		if c == '\\' || c == p.quote || c < ' ' {
 			break
 		}
 
@@ -633,7 +888,7 @@ func (p *Parser) unquote(in []byte) ([]byte, bool, error) {
 				written += utf8.EncodeRune(out[written:], r)
 			}
 
-		case c == '"', c < ' ':
+		case c == p.quote, c < ' ':
 			return nil, false, errUnquoteInvalidChar
 
 		case c < utf8.RuneSelf:
@@ -659,7 +914,8 @@ func (p *Parser) stepNumber(b []byte) ([]byte, bool, error) {
 		isStopChar := c == ' ' || c == '\t' || c == '\f' || c == '\n' || c == '\r' ||
 			c == ',' ||
 			c == ']' ||
-			c == '}'
+			c == '}' ||
+			(p.relaxed && c == '/')
 		if isStopChar {
 			stop = i
 			done = true
@@ -689,11 +945,17 @@ func (p *Parser) stepNumber(b []byte) ([]byte, bool, error) {
 func (p *Parser) reportNumber(b []byte, isDouble bool) error {
 	// parse number
 	var err error
-	if isDouble {
+	if p.relaxed && isHexNumber(b) {
+		err = p.reportHex(b)
+	} else if isDouble {
 		var f float64
 		if f, err = strconv.ParseFloat(bytes2Str(b), 64); err == nil {
 			err = p.visitor.OnFloat64(f)
+		} else {
+			err = &parseError{err}
 		}
+	} else if f, ok := p.parseNonFinite(b); ok {
+		err = p.visitor.OnFloat64(f)
 	} else {
 		var i int64
 		if i, err = parseInt(b); err == nil {
@@ -704,6 +966,63 @@ func (p *Parser) reportNumber(b []byte, isDouble bool) error {
 	return err
 }
 
+// parseNonFinite parses the signed Infinity literals, which start like a
+// number.
+func (p *Parser) parseNonFinite(b []byte) (float64, bool) {
+	if !p.allowNonFinite || len(b) < 2 || b[1] != 'I' {
+		return 0, false
+	}
+
+	switch bytes2Str(b) {
+	case "-Infinity":
+		return math.Inf(-1), true
+	case "+Infinity":
+		return math.Inf(1), true
+	}
+	return 0, false
+}
+
+func isHexNumber(b []byte) bool {
+	if len(b) > 0 && (b[0] == '-' || b[0] == '+') {
+		b = b[1:]
+	}
+	return len(b) > 2 && b[0] == '0' && (b[1] == 'x' || b[1] == 'X')
+}
+
+// reportHex reports hex numbers as int64. Positive values not fitting into
+// an int64 are reported as uint64.
+func (p *Parser) reportHex(b []byte) error {
+	u, neg, err := parseHex(b)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case neg && u > 1<<63:
+		return parseErrorf("hex number '%s' out of range", b)
+	case neg:
+		return p.visitor.OnInt64(-int64(u))
+	case u > math.MaxInt64:
+		return p.visitor.OnUint64(u)
+	default:
+		return p.visitor.OnInt64(int64(u))
+	}
+}
+
+func parseHex(b []byte) (u uint64, neg bool, err error) {
+	neg = b[0] == '-'
+	digits := b
+	if b[0] == '-' || b[0] == '+' {
+		digits = digits[1:]
+	}
+
+	u, err = strconv.ParseUint(bytes2Str(digits[2:]), 16, 64)
+	if err != nil {
+		return 0, false, parseErrorf("'%s' is no valid hex number", b)
+	}
+	return u, neg, nil
+}
+
 func parseInt(b []byte) (int64, error) {
 	neg := false
 	if b[0] == '+' {
@@ -730,17 +1049,17 @@ func parseUint(b []byte) (uint64, error) {
 	for _, c := range b {
 		d := int(c) - '0'
 		if d < 0 || d > 9 {
-			return 0, fmt.Errorf("'%s' is no valid number", b)
+			return 0, parseErrorf("'%s' is no valid number", b)
 		}
 
 		if n >= cutoff {
-			return 0, fmt.Errorf("number overflow parsing '%v'", b)
+			return 0, parseErrorf("number overflow parsing '%v'", b)
 		}
 
 		n *= 10
 		n1 := n + uint64(d)
 		if n1 < n || n1 > maxVal {
-			return 0, fmt.Errorf("number overflow parsing '%v'", b)
+			return 0, parseErrorf("number overflow parsing '%v'", b)
 		}
 
 		n = n1
@@ -773,6 +1092,22 @@ func (p *Parser) stepFALSE(b []byte) ([]byte, bool, error) {
 	return b, done, err
 }
 
+func (p *Parser) stepNaN(b []byte) ([]byte, bool, error) {
+	b, done, err := p.stepKind(b, []byte("NaN"), errExpectedNaN)
+	if done {
+		err = p.visitor.OnFloat64(math.NaN())
+	}
+	return b, done, err
+}
+
+func (p *Parser) stepInfinity(b []byte) ([]byte, bool, error) {
+	b, done, err := p.stepKind(b, []byte("Infinity"), errExpectedInfinity)
+	if done {
+		err = p.visitor.OnFloat64(math.Inf(1))
+	}
+	return b, done, err
+}
+
 func (p *Parser) stepKind(b []byte, kind []byte, err error) ([]byte, bool, error) {
 	n := p.required
 	s := kind[len(kind)-n:]
@@ -798,6 +1133,62 @@ func isDigit(c byte) bool {
 	return '0' <= c && c <= '9'
 }
 
+func isIdentStart(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c == '_' || c == '$'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+// skipSpace removes leading whitespace. In relaxed mode comments are removed
+// as well. The comment state is kept in the parser, such that comments can
+// span multiple input buffers.
+func (p *Parser) skipSpace(b []byte) ([]byte, error) {
+	if !p.relaxed {
+		return trimLeft(b), nil
+	}
+
+	for i, c := range b {
+		switch p.comment {
+		case noComment:
+			if c == '/' {
+				p.comment = commentStart
+			} else if !unicode.IsSpace(rune(c)) {
+				return b[i:], nil
+			}
+
+		case commentStart:
+			switch c {
+			case '/':
+				p.comment = lineComment
+			case '*':
+				p.comment = blockComment
+			default:
+				return nil, errExpectedComment
+			}
+
+		case lineComment:
+			if c == '\n' {
+				p.comment = noComment
+			}
+
+		case blockComment:
+			if c == '*' {
+				p.comment = blockCommentEnd
+			}
+
+		case blockCommentEnd:
+			if c == '/' {
+				p.comment = noComment
+			} else if c != '*' {
+				p.comment = blockComment
+			}
+		}
+	}
+	return nil, nil
+}
+
 func trimLeft(b []byte) []byte {
 	for i, c := range b {
 		if !unicode.IsSpace(rune(c)) {
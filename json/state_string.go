@@ -21,9 +21,9 @@ package json
 
 import "strconv"
 
-const _state_name = "failedStatestartStatearrStatearrStateValuearrStateNextdictStatedictFieldStatedictNextFieldStatedictFieldValuedictFieldValueSepdictFieldStateEndnullStatetrueStatefalseStatestringStatenumberState"
+const _state_name = "failedStatestartStatearrStatearrStateValuearrStateNextdictStatedictFieldStatedictNextFieldStatedictFieldValuedictFieldValueSepdictFieldStateEndnullStatetrueStatefalseStatestringStatenumberStatenanStateinfinityStatedictFieldIdentState"
 
-var _state_index = [...]uint8{0, 11, 21, 29, 42, 54, 63, 77, 95, 109, 126, 143, 152, 161, 171, 182, 193}
+var _state_index = [...]uint8{0, 11, 21, 29, 42, 54, 63, 77, 95, 109, 126, 143, 152, 161, 171, 182, 193, 201, 214, 233}
 
 func (i state) String() string {
 	if i >= state(len(_state_index)-1) {
@@ -38,8 +38,36 @@ type Visitor struct {
 	inArray boolStack
 
 	escapeSet []bool
+
+	nonFinite NonFinitePolicy
+
+	// pretty printing
+	pretty  bool
+	prefix  string
+	indent  string
+	newline bool
 }
 
+// NonFinitePolicy configures how the Visitor encodes the float values NaN,
+// +Inf and -Inf, which are not supported by standard JSON.
+type NonFinitePolicy uint8
+
+const (
+	// NonFiniteError fails with an error (default).
+	NonFiniteError NonFinitePolicy = iota
+
+	// NonFiniteNull encodes non-finite values as null.
+	NonFiniteNull
+
+	// NonFiniteString encodes non-finite values as the strings "NaN",
+	// "Infinity" and "-Infinity".
+	NonFiniteString
+
+	// NonFiniteLiteral encodes non-finite values as the non-standard JSON5
+	// literals NaN, Infinity and -Infinity. See (*Parser).SetAllowNonFinite.
+	NonFiniteLiteral
+)
+
 type boolStack struct {
 	stack   []bool
 	stack0  [32]bool
@@ -92,6 +120,28 @@ func (v *Visitor) SetEscapeHTML(b bool) {
 	}
 }
 
+// SetNonFinitePolicy configures the encoding of NaN and infinite float
+// values.
+func (v *Visitor) SetNonFinitePolicy(p NonFinitePolicy) {
+	v.nonFinite = p
+}
+
+// SetIndent configures the visitor to pretty print the output. Each JSON
+// element in an array or object begins on a new line, starting with prefix
+// followed by one or more copies of indent according to the nesting depth.
+// Calling SetIndent("", "") restores the compact output.
+func (v *Visitor) SetIndent(prefix, indent string) {
+	v.prefix = prefix
+	v.indent = indent
+	v.pretty = prefix != "" || indent != ""
+}
+
+// SetNewline configures the visitor to write a newline after each top-level
+// document.
+func (v *Visitor) SetNewline(b bool) {
+	v.newline = b
+}
+
 func (vs *Visitor) writeByte(b byte) error {
 	vs.scratch[0] = b
 	return vs.w.write(vs.scratch[:1])
@@ -112,9 +162,7 @@ func (vs *Visitor) OnObjectStart(_ int, _ structform.BaseType) error {
 }
 
 func (vs *Visitor) OnObjectFinished() error {
-	vs.first.pop()
-	vs.inArray.pop()
-	return vs.writeByte('}')
+	return vs.onStructFinished('}')
 }
 
 func (vs *Visitor) OnKeyRef(s []byte) error {
@@ -124,7 +172,7 @@ func (vs *Visitor) OnKeyRef(s []byte) error {
 
 	err := vs.OnStringRef(s)
 	if err == nil {
-		err = vs.writeByte(':')
+		err = vs.writeColon()
 	}
 	return err
 }
@@ -136,7 +184,7 @@ func (vs *Visitor) OnKey(s string) error {
 
 	err := vs.OnString(s)
 	if err == nil {
-		err = vs.writeByte(':')
+		err = vs.writeColon()
 	}
 	return err
 }
@@ -144,9 +192,50 @@ func (vs *Visitor) OnKey(s string) error {
 func (vs *Visitor) onFieldNext() error {
 	if vs.first.current {
 		vs.first.current = false
-		return nil
+	} else if err := vs.writeByte(','); err != nil {
+		return err
+	}
+
+	if vs.pretty {
+		return vs.writeIndent()
+	}
+	return nil
+}
+
+func (vs *Visitor) writeColon() error {
+	if vs.pretty {
+		return vs.w.write(colonSpaceSymbol)
+	}
+	return vs.writeByte(':')
+}
+
+// writeIndent starts a new line, indented according to the current nesting
+// depth.
+func (vs *Visitor) writeIndent() error {
+	if err := vs.writeByte('\n'); err != nil {
+		return err
+	}
+	if vs.prefix != "" {
+		if err := vs.writeString(vs.prefix); err != nil {
+			return err
+		}
+	}
+	if vs.indent != "" {
+		for i := len(vs.first.stack); i > 0; i-- {
+			if err := vs.writeString(vs.indent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// onValueEnd writes the newline after a top-level document if configured.
+func (vs *Visitor) onValueEnd() error {
+	if vs.newline && len(vs.first.stack) == 0 {
+		return vs.writeByte('\n')
 	}
-	return vs.writeByte(',')
+	return nil
 }
 
 func (vs *Visitor) OnArrayStart(_ int, _ structform.BaseType) error {
@@ -160,9 +249,23 @@ func (vs *Visitor) OnArrayStart(_ int, _ structform.BaseType) error {
 }
 
 func (vs *Visitor) OnArrayFinished() error {
+	return vs.onStructFinished(']')
+}
+
+func (vs *Visitor) onStructFinished(end byte) error {
+	empty := vs.first.current
 	vs.first.pop()
 	vs.inArray.pop()
-	return vs.writeByte(']')
+
+	if vs.pretty && !empty {
+		if err := vs.writeIndent(); err != nil {
+			return err
+		}
+	}
+	if err := vs.writeByte(end); err != nil {
+		return err
+	}
+	return vs.onValueEnd()
 }
 
 func (vs *Visitor) tryElemNext() error {
@@ -172,9 +275,14 @@ func (vs *Visitor) tryElemNext() error {
 
 	if vs.first.current {
 		vs.first.current = false
-		return nil
+	} else if err := vs.w.write(commaSymbol); err != nil {
+		return err
+	}
+
+	if vs.pretty {
+		return vs.writeIndent()
 	}
-	return vs.w.write(commaSymbol)
+	return nil
 }
 
 var hex = "0123456789abcdef"
@@ -262,7 +370,7 @@ func (vs *Visitor) OnString(s string) error {
 		vs.writeString(s[start:])
 	}
 	vs.writeByte('"')
-	return nil
+	return vs.onValueEnd()
 }
 
 func (vs *Visitor) OnBool(b bool) error {
@@ -276,7 +384,10 @@ func (vs *Visitor) OnBool(b bool) error {
 	} else {
 		err = vs.w.write(falseSymbol)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return vs.onValueEnd()
 }
 
 func (vs *Visitor) OnNil() error {
@@ -284,8 +395,10 @@ func (vs *Visitor) OnNil() error {
 		return err
 	}
 
-	err := vs.w.write(nullSymbol)
-	return err
+	if err := vs.w.write(nullSymbol); err != nil {
+		return err
+	}
+	return vs.onValueEnd()
 }
 
 func (vs *Visitor) OnInt8(i int8) error {
@@ -317,8 +430,10 @@ func (vs *Visitor) onInt(v int64) error {
 		b := strconv.AppendInt(vs.scratch[:0], i, 10)
 		_, err := vs.w.Write(b)
 	*/
-	vs.onNumber(v < 0, uint64(v))
-	return nil
+	if err := vs.onNumber(v < 0, uint64(v)); err != nil {
+		return err
+	}
+	return vs.onValueEnd()
 }
 
 func (vs *Visitor) OnUint8(u uint8) error {
@@ -350,7 +465,10 @@ func (vs *Visitor) onUint(u uint64) error {
 		return err
 	}
 
-	return vs.onNumber(false, u)
+	if err := vs.onNumber(false, u); err != nil {
+		return err
+	}
+	return vs.onValueEnd()
 	/*
 		b := strconv.AppendUint(vs.scratch[:0], u, 10)
 		_, err := vs.w.Write(b)
@@ -412,13 +530,41 @@ func (vs *Visitor) onFloat(f float64, bits int) error {
 		return err
 	}
 
+	var b []byte
 	if math.IsInf(f, 0) || math.IsNaN(f) {
-		return fmt.Errorf("unsupported float value: %v", f)
+		switch vs.nonFinite {
+		case NonFiniteNull:
+			b = nullSymbol
+		case NonFiniteString, NonFiniteLiteral:
+			b = nonFiniteSymbol(f, vs.nonFinite == NonFiniteString)
+		default:
+			return fmt.Errorf("unsupported float value: %v", f)
+		}
+	} else {
+		b = strconv.AppendFloat(vs.scratch[:0], f, 'g', -1, bits)
 	}
 
-	b := strconv.AppendFloat(vs.scratch[:0], f, 'g', -1, bits)
-	err := vs.w.write(b)
-	return err
+	if err := vs.w.write(b); err != nil {
+		return err
+	}
+	return vs.onValueEnd()
+}
+
+func nonFiniteSymbol(f float64, quoted bool) []byte {
+	var sym []byte
+	switch {
+	case math.IsNaN(f):
+		sym = nanSymbol
+	case f > 0:
+		sym = posInfSymbol
+	default:
+		sym = negInfSymbol
+	}
+
+	if quoted {
+		return sym
+	}
+	return sym[1 : len(sym)-1]
 }
 
 func (s *boolStack) init() {
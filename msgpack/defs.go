@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import "github.com/elastic/go-structform/internal/unsafe"
+
+const (
+	codeNil   uint8 = 0xc0
+	codeFalse uint8 = 0xc2
+	codeTrue  uint8 = 0xc3
+
+	codeBin8  uint8 = 0xc4
+	codeBin16 uint8 = 0xc5
+	codeBin32 uint8 = 0xc6
+
+	codeExt8  uint8 = 0xc7
+	codeExt16 uint8 = 0xc8
+	codeExt32 uint8 = 0xc9
+
+	codeFloat32 uint8 = 0xca
+	codeFloat64 uint8 = 0xcb
+
+	codeUint8  uint8 = 0xcc
+	codeUint16 uint8 = 0xcd
+	codeUint32 uint8 = 0xce
+	codeUint64 uint8 = 0xcf
+
+	codeInt8  uint8 = 0xd0
+	codeInt16 uint8 = 0xd1
+	codeInt32 uint8 = 0xd2
+	codeInt64 uint8 = 0xd3
+
+	codeFixExt1  uint8 = 0xd4
+	codeFixExt16 uint8 = 0xd8
+
+	codeStr8  uint8 = 0xd9
+	codeStr16 uint8 = 0xda
+	codeStr32 uint8 = 0xdb
+
+	codeArr16 uint8 = 0xdc
+	codeArr32 uint8 = 0xdd
+	codeMap16 uint8 = 0xde
+	codeMap32 uint8 = 0xdf
+)
+
+// fixed size type ranges. The fix* codes encode the length or value in the
+// lower bits of the type code.
+const (
+	fixMapMask uint8 = 0xf0
+	fixMap     uint8 = 0x80
+	fixMapMax        = 0x0f
+
+	fixArrMask uint8 = 0xf0
+	fixArr     uint8 = 0x90
+	fixArrMax        = 0x0f
+
+	fixStrMask uint8 = 0xe0
+	fixStr     uint8 = 0xa0
+	fixStrMax        = 0x1f
+
+	posFixIntMax uint8 = 0x7f
+	negFixIntMin uint8 = 0xe0
+)
+
+func str2Bytes(s string) []byte {
+	return unsafe.Str2Bytes(s)
+}
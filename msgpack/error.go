@@ -0,0 +1,40 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import "errors"
+
+var errInvalidCode = newParseError("invalid type code")
+var errTextKeyRequired = newParseError("only string keys supported")
+var errExtUnsupported = newParseError("extension types not supported")
+var errUnbalanced = errors.New("unbalanced array or object")
+var errBinLength = errors.New("number of bytes does not match bin length")
+
+// parseError marks errors detected by the parser. Only parse errors are
+// reported as structform.SyntaxError, errors returned by the visitor are
+// passed through unchanged.
+type parseError struct {
+	err error
+}
+
+func newParseError(msg string) error {
+	return &parseError{errors.New(msg)}
+}
+
+func (e *parseError) Error() string { return e.err.Error() }
+func (e *parseError) Unwrap() error { return e.err }
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import (
+	"bytes"
+	"testing"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/sftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	tests := []struct {
+		title    string
+		rec      sftest.Recording
+		expected []byte
+	}{
+		{"nil", sftest.Recording{sftest.NilRec{}}, []byte{0xc0}},
+		{"positive fixint", sftest.Recording{sftest.Int64Rec{5}}, []byte{0x05}},
+		{"negative fixint", sftest.Recording{sftest.Int8Rec{-1}}, []byte{0xff}},
+		{"uint8", sftest.Recording{sftest.Uint16Rec{200}}, []byte{0xcc, 200}},
+		{"int16", sftest.Recording{sftest.Int64Rec{-300}}, []byte{0xd1, 0xfe, 0xd4}},
+		{"fixstr", sftest.Recording{sftest.StringRec{"ab"}}, []byte{0xa2, 'a', 'b'}},
+		{
+			"array with unknown length",
+			sftest.Recording(sftest.Arr(-1, structform.AnyType, sftest.IntRec{1}, sftest.Arr(-1, structform.AnyType, sftest.BoolRec{true}))),
+			[]byte{0x92, 0x01, 0x91, 0xc3},
+		},
+		{
+			"object with unknown length",
+			sftest.Recording(sftest.Obj(-1, structform.AnyType, "a", sftest.NilRec{})),
+			[]byte{0x81, 0xa1, 'a', 0xc0},
+		},
+		{
+			"bytes",
+			sftest.Recording(sftest.Arr(2, structform.ByteType, sftest.ByteRec{1}, sftest.ByteRec{2})),
+			[]byte{0xc4, 0x02, 0x01, 0x02},
+		},
+		{
+			"bytes with unknown length",
+			sftest.Recording(sftest.Arr(-1, structform.ByteType, sftest.ByteRec{1}, sftest.ByteRec{2})),
+			[]byte{0xc4, 0x02, 0x01, 0x02},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			if err := test.rec.Replay(NewVisitor(buf)); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expected, buf.Bytes())
+		})
+	}
+}
+
+func TestParseEncodeRoundTrip(t *testing.T) {
+	withBody := func(hdr []byte, elem []byte, n int) []byte {
+		return append(hdr, bytes.Repeat(elem, n)...)
+	}
+
+	tests := map[string][]byte{
+		"bin8 empty":          {0xc4, 0x00},
+		"bin8":                {0xc4, 0x03, 0x01, 0x02, 0x03},
+		"bin16":               withBody([]byte{0xc5, 0x01, 0x00}, []byte{0xab}, 1<<8),
+		"bin32":               withBody([]byte{0xc6, 0x00, 0x01, 0x00, 0x00}, []byte{0xab}, 1<<16),
+		"str8":                withBody([]byte{0xd9, 0x20}, []byte{'a'}, 32),
+		"str16":               withBody([]byte{0xda, 0x01, 0x00}, []byte{'a'}, 1<<8),
+		"str32":               withBody([]byte{0xdb, 0x00, 0x01, 0x00, 0x00}, []byte{'a'}, 1<<16),
+		"array16":             withBody([]byte{0xdc, 0x00, 0x10}, []byte{0xc0}, 16),
+		"array32":             withBody([]byte{0xdd, 0x00, 0x01, 0x00, 0x00}, []byte{0xc0}, 1<<16),
+		"map16":               withBody([]byte{0xde, 0x00, 0x10}, []byte{0xa0, 0xc0}, 16),
+		"map32":               withBody([]byte{0xdf, 0x00, 0x01, 0x00, 0x00}, []byte{0xa0, 0xc0}, 1<<16),
+		"positive fixint max": {0x7f},
+		"negative fixint min": {0xe0},
+		"uint8 max":           {0xcc, 0xff},
+		"uint16 max":          {0xcd, 0xff, 0xff},
+		"uint32 max":          {0xce, 0xff, 0xff, 0xff, 0xff},
+		"uint64 max":          {0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"int64 max":           {0xcf, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"int8 min":            {0xd0, 0x80},
+		"int16 min":           {0xd1, 0x80, 0x00},
+		"int32 min":           {0xd2, 0x80, 0x00, 0x00, 0x00},
+		"int64 min":           {0xd3, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		"float32":             {0xca, 0x3f, 0xc0, 0x00, 0x00},
+		"float64":             {0xcb, 0x3f, 0xf8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	}
+
+	for name, in := range tests {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			if err := Parse(in, NewVisitor(buf)); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, in, buf.Bytes())
+		})
+	}
+}
+
+func TestEncParseConsistent(t *testing.T) {
+	testEncParseConsistent(t, Parse)
+}
+
+func TestEncDecoderConsistent(t *testing.T) {
+	testEncParseConsistent(t, func(content []byte, to structform.Visitor) error {
+		dec := NewBytesDecoder(content, to)
+		return dec.Next()
+	})
+}
+
+func TestEncParseBytesConsistent(t *testing.T) {
+	testEncParseConsistent(t, func(content []byte, to structform.Visitor) error {
+		p := NewParser(to)
+		for _, b := range content {
+			err := p.feed([]byte{b})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func testEncParseConsistent(
+	t *testing.T,
+	parse func([]byte, structform.Visitor) error,
+) {
+	sftest.TestEncodeParseConsistent(t, sftest.Samples,
+		func() (structform.Visitor, func(structform.Visitor) error) {
+			buf := bytes.NewBuffer(nil)
+			vs := NewVisitor(buf)
+
+			return vs, func(to structform.Visitor) error {
+				return parse(buf.Bytes(), to)
+			}
+		})
+}
+
+func TestParseErrorOffset(t *testing.T) {
+	var rec sftest.Recording
+	err := Parse([]byte{0x92, 0x01, 0xc1}, &rec)
+	serr, ok := err.(*structform.SyntaxError)
+	if !ok {
+		t.Fatalf("expected syntax error, got: %v", err)
+	}
+	assert.Equal(t, errInvalidCode, serr.Err)
+	assert.Equal(t, int64(2), serr.Offset)
+}
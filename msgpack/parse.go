@@ -0,0 +1,574 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	structform "github.com/elastic/go-structform"
+)
+
+type Parser struct {
+	visitor    structform.Visitor
+	strVisitor structform.StringRefVisitor
+
+	// last fail state
+	err error
+
+	// parser state machine
+	state stateStack
+
+	length lengthStack
+
+	buffer  []byte
+	buffer0 [64]byte
+
+	// input offset of the next byte to be processed
+	offset int64
+}
+
+type state struct {
+	major uint8
+	code  uint8 // type code of the value being parsed
+}
+
+// parser states
+const (
+	stFail   uint8 = iota + 1
+	stValue        // top-level value
+	stFixed        // fixed size number value
+	stLen          // length prefix of str, bin, array or map
+	stKeyLen       // length prefix of map key
+	stStr          // string contents
+	stBin          // binary contents, streamed via array visitor
+	stArr          // next array element
+	stMap          // next map key
+	stKey          // map key contents
+	stElem         // map value
+)
+
+func NewParser(vs structform.Visitor) *Parser {
+	p := &Parser{}
+	p.init(vs)
+	return p
+}
+
+func ParseReader(in io.Reader, vs structform.Visitor) (int64, error) {
+	p := NewParser(vs)
+	i, err := io.Copy(p, in)
+	return i, err
+}
+
+func Parse(b []byte, vs structform.Visitor) error {
+	return NewParser(vs).Parse(b)
+}
+
+func ParseString(str string, vs structform.Visitor) error {
+	return NewParser(vs).ParseString(str)
+}
+
+func (p *Parser) init(vs structform.Visitor) {
+	*p = Parser{
+		visitor:    vs,
+		strVisitor: structform.MakeStringRefVisitor(vs),
+	}
+	p.buffer = p.buffer0[:0]
+	p.length.init()
+	p.state.init(state{stValue, 0})
+}
+
+func (p *Parser) Write(b []byte) (int, error) {
+	p.err = p.feed(b)
+	if p.err != nil {
+		return 0, p.err
+	}
+	return len(b), nil
+}
+
+func (p *Parser) ParseString(str string) error {
+	return p.Parse(str2Bytes(str))
+}
+
+func (p *Parser) Parse(b []byte) error {
+	return p.feed(b)
+}
+
+func (p *Parser) feed(b []byte) error {
+	for len(b) > 0 {
+		n, _, err := p.feedUntil(b)
+		if err != nil {
+			return err
+		}
+
+		b = b[n:]
+	}
+
+	return nil
+}
+
+func (p *Parser) feedUntil(b []byte) (int, bool, error) {
+	var (
+		orig = b
+		last []byte
+		done bool
+		err  error
+	)
+
+	for len(b) > 0 {
+		last = b
+		b, done, err = p.execStep(b)
+		if done || err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		if b == nil {
+			b = last
+		}
+		consumed := len(orig) - len(b)
+		return consumed, false, p.syntaxError(err, consumed)
+	}
+
+	p.offset += int64(len(orig) - len(b))
+	return len(orig) - len(b), done, nil
+}
+
+// syntaxError annotates parse errors with the input offset after processing
+// another n bytes. Errors returned by the visitor are passed through
+// unchanged.
+func (p *Parser) syntaxError(err error, n int) error {
+	if _, ok := err.(*parseError); !ok {
+		return err // already annotated or returned by the visitor
+	}
+	return &structform.SyntaxError{Err: err, Offset: p.offset + int64(n)}
+}
+
+func (p *Parser) execStep(b []byte) ([]byte, bool, error) {
+	var (
+		err  error
+		done bool
+	)
+
+	switch p.state.current.major {
+	case stFail:
+		return b, false, p.err
+	case stValue, stArr, stElem:
+		b, done, err = p.stepValue(b)
+	case stFixed:
+		b, done, err = p.stepFixed(b)
+	case stLen, stKeyLen:
+		b, done, err = p.stepLen(b)
+	case stStr:
+		b, done, err = p.stepStr(b)
+	case stBin:
+		b, done, err = p.stepBin(b)
+	case stMap:
+		b, done, err = p.stepMapKey(b)
+	case stKey:
+		b, err = p.stepKey(b)
+	default:
+		err = errInvalidCode
+	}
+
+	return b, done, err
+}
+
+func (p *Parser) popState() (bool, error) {
+	p.state.pop()
+	return p.onValue()
+}
+
+// onValue updates the parent array or map after a value has been reported,
+// reporting the end of all arrays and maps that have been completed.
+// Returns true if the top-level value has been fully parsed.
+func (p *Parser) onValue() (bool, error) {
+	for {
+		switch p.state.current.major {
+		case stArr:
+			if p.length.current--; p.length.current > 0 {
+				return false, nil
+			}
+			if err := p.visitor.OnArrayFinished(); err != nil {
+				return false, err
+			}
+
+		case stElem:
+			p.state.current.major = stMap
+			if p.length.current--; p.length.current > 0 {
+				return false, nil
+			}
+			if err := p.visitor.OnObjectFinished(); err != nil {
+				return false, err
+			}
+
+		default:
+			return true, nil
+		}
+
+		p.length.pop()
+		p.state.pop()
+	}
+}
+
+func (p *Parser) stepValue(b []byte) ([]byte, bool, error) {
+	var (
+		code = b[0]
+		err  error
+	)
+
+	b = b[1:]
+	switch {
+	case code <= posFixIntMax:
+		err = p.visitor.OnUint8(code)
+	case code >= negFixIntMin:
+		err = p.visitor.OnInt8(int8(code))
+	case code&fixMapMask == fixMap:
+		done, err := p.initMap(int64(code &^ fixMapMask))
+		return b, done, err
+	case code&fixArrMask == fixArr:
+		done, err := p.initArr(int64(code &^ fixArrMask))
+		return b, done, err
+	case code&fixStrMask == fixStr:
+		done, err := p.initStr(int64(code &^ fixStrMask))
+		return b, done, err
+
+	default:
+		switch code {
+		case codeNil:
+			err = p.visitor.OnNil()
+		case codeFalse:
+			err = p.visitor.OnBool(false)
+		case codeTrue:
+			err = p.visitor.OnBool(true)
+
+		case codeFloat32, codeFloat64,
+			codeUint8, codeUint16, codeUint32, codeUint64,
+			codeInt8, codeInt16, codeInt32, codeInt64:
+			p.state.push(state{stFixed, code})
+			return b, false, nil
+
+		case codeBin8, codeBin16, codeBin32,
+			codeStr8, codeStr16, codeStr32,
+			codeArr16, codeArr32, codeMap16, codeMap32:
+			p.state.push(state{stLen, code})
+			return b, false, nil
+
+		case codeExt8, codeExt16, codeExt32:
+			return nil, false, errExtUnsupported
+		default:
+			if codeFixExt1 <= code && code <= codeFixExt16 {
+				return nil, false, errExtUnsupported
+			}
+			return nil, false, errInvalidCode
+		}
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	done, err := p.onValue()
+	return b, done, err
+}
+
+func (p *Parser) stepFixed(b []byte) ([]byte, bool, error) {
+	code := p.state.current.code
+	b, tmp := p.collect(b, numBytes(code))
+	if tmp == nil {
+		return nil, false, nil
+	}
+
+	var err error
+	switch code {
+	case codeFloat32:
+		err = p.visitor.OnFloat32(math.Float32frombits(readUint32(tmp)))
+	case codeFloat64:
+		err = p.visitor.OnFloat64(math.Float64frombits(readUint64(tmp)))
+	case codeUint8:
+		err = p.visitor.OnUint8(tmp[0])
+	case codeUint16:
+		err = p.visitor.OnUint16(readUint16(tmp))
+	case codeUint32:
+		err = p.visitor.OnUint32(readUint32(tmp))
+	case codeUint64:
+		err = p.visitor.OnUint64(readUint64(tmp))
+	case codeInt8:
+		err = p.visitor.OnInt8(int8(tmp[0]))
+	case codeInt16:
+		err = p.visitor.OnInt16(int16(readUint16(tmp)))
+	case codeInt32:
+		err = p.visitor.OnInt32(int32(readUint32(tmp)))
+	case codeInt64:
+		err = p.visitor.OnInt64(int64(readUint64(tmp)))
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	done, err := p.popState()
+	return b, done, err
+}
+
+func (p *Parser) stepLen(b []byte) ([]byte, bool, error) {
+	st := p.state.current
+	b, tmp := p.collect(b, numBytes(st.code))
+	if tmp == nil {
+		return nil, false, nil
+	}
+
+	var l int64
+	switch len(tmp) {
+	case 1:
+		l = int64(tmp[0])
+	case 2:
+		l = int64(readUint16(tmp))
+	default:
+		l = int64(readUint32(tmp))
+	}
+
+	p.state.pop()
+	if st.major == stKeyLen {
+		return b, false, p.initKey(l)
+	}
+
+	var (
+		done bool
+		err  error
+	)
+	switch st.code {
+	case codeBin8, codeBin16, codeBin32:
+		done, err = p.initBin(l)
+	case codeStr8, codeStr16, codeStr32:
+		done, err = p.initStr(l)
+	case codeArr16, codeArr32:
+		done, err = p.initArr(l)
+	default:
+		done, err = p.initMap(l)
+	}
+	return b, done, err
+}
+
+func (p *Parser) initArr(l int64) (bool, error) {
+	if err := p.visitor.OnArrayStart(int(l), structform.AnyType); err != nil {
+		return false, err
+	}
+	if l == 0 {
+		return p.finishArr()
+	}
+
+	p.state.push(state{stArr, 0})
+	p.length.push(l)
+	return false, nil
+}
+
+func (p *Parser) initMap(l int64) (bool, error) {
+	if err := p.visitor.OnObjectStart(int(l), structform.AnyType); err != nil {
+		return false, err
+	}
+	if l == 0 {
+		if err := p.visitor.OnObjectFinished(); err != nil {
+			return false, err
+		}
+		return p.onValue()
+	}
+
+	p.state.push(state{stMap, 0})
+	p.length.push(l)
+	return false, nil
+}
+
+func (p *Parser) initBin(l int64) (bool, error) {
+	if err := p.visitor.OnArrayStart(int(l), structform.ByteType); err != nil {
+		return false, err
+	}
+	if l == 0 {
+		return p.finishArr()
+	}
+
+	p.state.push(state{stBin, 0})
+	p.length.push(l)
+	return false, nil
+}
+
+func (p *Parser) finishArr() (bool, error) {
+	if err := p.visitor.OnArrayFinished(); err != nil {
+		return false, err
+	}
+	return p.onValue()
+}
+
+func (p *Parser) initStr(l int64) (bool, error) {
+	if l == 0 {
+		if err := p.visitor.OnString(""); err != nil {
+			return false, err
+		}
+		return p.onValue()
+	}
+
+	p.state.push(state{stStr, 0})
+	p.length.push(l)
+	return false, nil
+}
+
+func (p *Parser) initKey(l int64) error {
+	if l == 0 {
+		if err := p.visitor.OnKey(""); err != nil {
+			return err
+		}
+		p.state.current.major = stElem
+		return nil
+	}
+
+	p.state.push(state{stKey, 0})
+	p.length.push(l)
+	return nil
+}
+
+func (p *Parser) stepStr(b []byte) ([]byte, bool, error) {
+	b, tmp := p.collect(b, int(p.length.current))
+	if tmp == nil {
+		return nil, false, nil
+	}
+
+	p.length.pop()
+	if err := p.strVisitor.OnStringRef(tmp); err != nil {
+		return nil, false, err
+	}
+
+	done, err := p.popState()
+	return b, done, err
+}
+
+func (p *Parser) stepBin(b []byte) ([]byte, bool, error) {
+	// stream raw bytes via array visitor
+
+	L := int(p.length.current)
+	done := len(b) >= L
+	if !done {
+		L = len(b)
+		p.length.current -= int64(L)
+	}
+
+	for _, c := range b[:L] {
+		if err := p.visitor.OnByte(c); err != nil {
+			return nil, false, err
+		}
+	}
+
+	b = b[L:]
+	if !done {
+		return b, false, nil
+	}
+
+	p.length.pop()
+	p.state.pop()
+	done, err := p.finishArr()
+	return b, done, err
+}
+
+func (p *Parser) stepMapKey(b []byte) ([]byte, bool, error) {
+	code := b[0]
+	b = b[1:]
+
+	switch {
+	case code&fixStrMask == fixStr:
+		return b, false, p.initKey(int64(code &^ fixStrMask))
+	case code == codeStr8, code == codeStr16, code == codeStr32:
+		p.state.push(state{stKeyLen, code})
+		return b, false, nil
+	default:
+		return nil, false, errTextKeyRequired
+	}
+}
+
+func (p *Parser) stepKey(b []byte) ([]byte, error) {
+	b, tmp := p.collect(b, int(p.length.current))
+	if tmp == nil {
+		return nil, nil
+	}
+
+	if err := p.strVisitor.OnKeyRef(tmp); err != nil {
+		return nil, err
+	}
+
+	p.length.pop()
+	p.state.pop()
+	p.state.current.major = stElem
+	return b, nil
+}
+
+func (p *Parser) collect(b []byte, count int) ([]byte, []byte) {
+	if len(p.buffer) > 0 {
+		delta := count - len(p.buffer)
+		if delta > 0 {
+			N := delta
+			complete := true
+			if N > len(b) {
+				complete = false
+				N = len(b)
+			}
+
+			p.buffer = append(p.buffer, b[:N]...)
+			if !complete {
+				return nil, nil
+			}
+
+			// advance read buffer
+			b = b[N:]
+		}
+
+		if len(p.buffer) >= count {
+			tmp := p.buffer[:count]
+			if len(p.buffer) == count {
+				p.buffer = p.buffer0[:0]
+			} else {
+				p.buffer = p.buffer[count:]
+			}
+			return b, tmp
+		}
+	}
+
+	if len(b) >= count {
+		return b[count:], b[:count]
+	}
+
+	p.buffer = append(p.buffer, b...)
+	return nil, nil
+}
+
+// numBytes returns the size of the fixed size value or length prefix
+// following the type code.
+func numBytes(code uint8) int {
+	switch code {
+	case codeUint8, codeInt8, codeBin8, codeStr8:
+		return 1
+	case codeUint16, codeInt16, codeBin16, codeStr16, codeArr16, codeMap16:
+		return 2
+	case codeUint64, codeInt64, codeFloat64:
+		return 8
+	default:
+		return 4
+	}
+}
+
+func readUint16(b []byte) uint16 { return binary.BigEndian.Uint16(b) }
+func readUint32(b []byte) uint32 { return binary.BigEndian.Uint32(b) }
+func readUint64(b []byte) uint64 { return binary.BigEndian.Uint64(b) }
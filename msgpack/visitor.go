@@ -0,0 +1,812 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Visitor encodes the visited structure as MessagePack.
+//
+// MessagePack requires the number of elements to be known when starting an
+// array or map. Arrays and objects of unknown length (len < 0) are buffered
+// in memory until finished, such that the correct length can be written.
+// Arrays of type ByteType are written as bin.
+type Visitor struct {
+	w       writer
+	scratch [16]byte
+
+	containers containerStack
+}
+
+type writer struct {
+	out io.Writer
+
+	// encoded contents of open arrays/objects with unknown length
+	buf      []byte
+	buffered int
+}
+
+var _ structform.ExtVisitor = &Visitor{}
+
+func (w *writer) write(b []byte) error {
+	if w.buffered > 0 {
+		w.buf = append(w.buf, b...)
+		return nil
+	}
+
+	_, err := w.out.Write(b)
+	return err
+}
+
+func (w *writer) flush() error {
+	_, err := w.out.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func NewVisitor(out io.Writer) *Visitor {
+	v := &Visitor{w: writer{out: out}}
+	v.containers.init()
+	return v
+}
+
+func (vs *Visitor) writeByte(b byte) error {
+	vs.scratch[0] = b
+	return vs.w.write(vs.scratch[:1])
+}
+
+// onValue counts the elements of the current array.
+func (vs *Visitor) onValue() {
+	if vs.containers.current.array {
+		vs.containers.current.count++
+	}
+}
+
+func (vs *Visitor) OnObjectStart(len int, baseType structform.BaseType) error {
+	return vs.structStart(false, len)
+}
+
+func (vs *Visitor) OnObjectFinished() error {
+	return vs.structEnd(false)
+}
+
+func (vs *Visitor) OnKey(s string) error {
+	vs.containers.current.count++
+	return vs.string(str2Bytes(s))
+}
+
+func (vs *Visitor) OnKeyRef(s []byte) error {
+	vs.containers.current.count++
+	return vs.string(s)
+}
+
+func (vs *Visitor) OnArrayStart(len int, baseType structform.BaseType) error {
+	if baseType == structform.ByteType {
+		return vs.binStart(len)
+	}
+	return vs.structStart(true, len)
+}
+
+func (vs *Visitor) OnArrayFinished() error {
+	return vs.structEnd(true)
+}
+
+func (vs *Visitor) structStart(array bool, l int) error {
+	vs.onValue()
+
+	c := container{array: array, len: l}
+	if l < 0 {
+		c.offset = len(vs.w.buf)
+		vs.w.buffered++
+		vs.containers.push(c)
+		return nil
+	}
+
+	vs.containers.push(c)
+	if array {
+		return vs.arrLen(l)
+	}
+	return vs.mapLen(l)
+}
+
+// binStart starts an array of bytes. The bytes reported via OnByte are
+// written as is, and the bin header is written once the length is known.
+func (vs *Visitor) binStart(l int) error {
+	vs.onValue()
+
+	c := container{array: true, bin: true, len: l}
+	if l < 0 {
+		c.offset = len(vs.w.buf)
+		vs.w.buffered++
+		vs.containers.push(c)
+		return nil
+	}
+
+	vs.containers.push(c)
+	return vs.w.write(vs.sizedHeader(codeBin8, codeBin16, codeBin32, l))
+}
+
+func (vs *Visitor) structEnd(array bool) error {
+	c, ok := vs.containers.pop()
+	if !ok || c.array != array {
+		return errUnbalanced
+	}
+	if c.len >= 0 {
+		if c.bin && c.count != c.len {
+			return errBinLength
+		}
+		return nil
+	}
+
+	// insert header in front of the buffered contents
+	var hdr []byte
+	switch {
+	case c.bin:
+		hdr = vs.sizedHeader(codeBin8, codeBin16, codeBin32, c.count)
+	case array:
+		hdr = vs.header(fixArr, codeArr16, codeArr32, c.count)
+	default:
+		hdr = vs.header(fixMap, codeMap16, codeMap32, c.count)
+	}
+
+	w := &vs.w
+	end := len(w.buf)
+	w.buf = append(w.buf, hdr...)
+	copy(w.buf[c.offset+len(hdr):], w.buf[c.offset:end])
+	copy(w.buf[c.offset:], hdr)
+
+	w.buffered--
+	if w.buffered == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+func (vs *Visitor) OnNil() error {
+	vs.onValue()
+	return vs.writeByte(codeNil)
+}
+
+func (vs *Visitor) OnBool(b bool) error {
+	vs.onValue()
+	return vs.bool(b)
+}
+
+func (vs *Visitor) OnString(s string) error {
+	vs.onValue()
+	return vs.string(str2Bytes(s))
+}
+
+func (vs *Visitor) OnStringRef(s []byte) error {
+	vs.onValue()
+	return vs.string(s)
+}
+
+func (vs *Visitor) OnInt8(i int8) error {
+	vs.onValue()
+	return vs.int64(int64(i))
+}
+
+func (vs *Visitor) OnInt16(i int16) error {
+	vs.onValue()
+	return vs.int64(int64(i))
+}
+
+func (vs *Visitor) OnInt32(i int32) error {
+	vs.onValue()
+	return vs.int64(int64(i))
+}
+
+func (vs *Visitor) OnInt64(i int64) error {
+	vs.onValue()
+	return vs.int64(i)
+}
+
+func (vs *Visitor) OnInt(i int) error {
+	vs.onValue()
+	return vs.int64(int64(i))
+}
+
+func (vs *Visitor) OnByte(b byte) error {
+	vs.onValue()
+	if vs.containers.current.bin {
+		return vs.writeByte(b)
+	}
+	return vs.uint64(uint64(b))
+}
+
+func (vs *Visitor) OnUint8(u uint8) error {
+	vs.onValue()
+	return vs.uint64(uint64(u))
+}
+
+func (vs *Visitor) OnUint16(u uint16) error {
+	vs.onValue()
+	return vs.uint64(uint64(u))
+}
+
+func (vs *Visitor) OnUint32(u uint32) error {
+	vs.onValue()
+	return vs.uint64(uint64(u))
+}
+
+func (vs *Visitor) OnUint64(u uint64) error {
+	vs.onValue()
+	return vs.uint64(u)
+}
+
+func (vs *Visitor) OnUint(u uint) error {
+	vs.onValue()
+	return vs.uint64(uint64(u))
+}
+
+func (vs *Visitor) OnFloat32(f float32) error {
+	vs.onValue()
+	return vs.float32(f)
+}
+
+func (vs *Visitor) OnFloat64(f float64) error {
+	vs.onValue()
+	return vs.float64(f)
+}
+
+func (vs *Visitor) OnBytes(b []byte) error {
+	vs.onValue()
+	return vs.bytes(b)
+}
+
+func (vs *Visitor) OnUint8Array(a []uint8) error {
+	vs.onValue()
+	return vs.bytes(a)
+}
+
+func (vs *Visitor) OnBoolArray(a []bool) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.bool(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnStringArray(a []string) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.string(str2Bytes(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt8Array(a []int8) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt16Array(a []int16) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt32Array(a []int32) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt64Array(a []int64) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.int64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnIntArray(a []int) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint16Array(a []uint16) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint32Array(a []uint32) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint64Array(a []uint64) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.uint64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUintArray(a []uint) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnFloat32Array(a []float32) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.float32(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnFloat64Array(a []float64) error {
+	vs.onValue()
+	if err := vs.arrLen(len(a)); err != nil {
+		return err
+	}
+	for _, v := range a {
+		if err := vs.float64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnBoolObject(m map[string]bool) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.bool(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnStringObject(m map[string]string) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.string(str2Bytes(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt8Object(m map[string]int8) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt16Object(m map[string]int16) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt32Object(m map[string]int32) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnInt64Object(m map[string]int64) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.int64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnIntObject(m map[string]int) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.int64(int64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint8Object(m map[string]uint8) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint16Object(m map[string]uint16) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint32Object(m map[string]uint32) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUint64Object(m map[string]uint64) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.uint64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnUintObject(m map[string]uint) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.uint64(uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnFloat32Object(m map[string]float32) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.float32(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) OnFloat64Object(m map[string]float64) error {
+	vs.onValue()
+	if err := vs.mapLen(len(m)); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if err := vs.string(str2Bytes(k)); err != nil {
+			return err
+		}
+		if err := vs.float64(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vs *Visitor) bool(b bool) error {
+	if b {
+		return vs.writeByte(codeTrue)
+	}
+	return vs.writeByte(codeFalse)
+}
+
+func (vs *Visitor) string(s []byte) error {
+	L := len(s)
+	switch {
+	case L <= fixStrMax:
+		vs.scratch[0] = fixStr | uint8(L)
+		if err := vs.w.write(vs.scratch[:1]); err != nil {
+			return err
+		}
+	default:
+		if err := vs.sized(codeStr8, codeStr16, codeStr32, L); err != nil {
+			return err
+		}
+	}
+	return vs.w.write(s)
+}
+
+func (vs *Visitor) bytes(b []byte) error {
+	if err := vs.sized(codeBin8, codeBin16, codeBin32, len(b)); err != nil {
+		return err
+	}
+	return vs.w.write(b)
+}
+
+// sized writes the type code and length prefix of str and bin types.
+func (vs *Visitor) sized(code8, code16, code32 uint8, l int) error {
+	return vs.w.write(vs.sizedHeader(code8, code16, code32, l))
+}
+
+// sizedHeader encodes the type code and length prefix of str and bin types
+// into the scratch buffer.
+func (vs *Visitor) sizedHeader(code8, code16, code32 uint8, l int) []byte {
+	switch {
+	case l <= math.MaxUint8:
+		vs.scratch[0], vs.scratch[1] = code8, uint8(l)
+		return vs.scratch[:2]
+	case l <= math.MaxUint16:
+		vs.scratch[0] = code16
+		binary.BigEndian.PutUint16(vs.scratch[1:], uint16(l))
+		return vs.scratch[:3]
+	default:
+		vs.scratch[0] = code32
+		binary.BigEndian.PutUint32(vs.scratch[1:], uint32(l))
+		return vs.scratch[:5]
+	}
+}
+
+func (vs *Visitor) arrLen(l int) error {
+	return vs.w.write(vs.header(fixArr, codeArr16, codeArr32, l))
+}
+
+func (vs *Visitor) mapLen(l int) error {
+	return vs.w.write(vs.header(fixMap, codeMap16, codeMap32, l))
+}
+
+// header encodes the array or map header into the scratch buffer.
+func (vs *Visitor) header(fix, code16, code32 uint8, l int) []byte {
+	switch {
+	case l <= fixArrMax:
+		vs.scratch[0] = fix | uint8(l)
+		return vs.scratch[:1]
+	case l <= math.MaxUint16:
+		vs.scratch[0] = code16
+		binary.BigEndian.PutUint16(vs.scratch[1:], uint16(l))
+		return vs.scratch[:3]
+	default:
+		vs.scratch[0] = code32
+		binary.BigEndian.PutUint32(vs.scratch[1:], uint32(l))
+		return vs.scratch[:5]
+	}
+}
+
+func (vs *Visitor) int64(v int64) error {
+	if v >= 0 {
+		return vs.uint64(uint64(v))
+	}
+
+	switch {
+	case v >= -32:
+		return vs.writeByte(uint8(v))
+	case v >= math.MinInt8:
+		vs.scratch[0], vs.scratch[1] = codeInt8, uint8(v)
+		return vs.w.write(vs.scratch[:2])
+	case v >= math.MinInt16:
+		vs.scratch[0] = codeInt16
+		binary.BigEndian.PutUint16(vs.scratch[1:], uint16(v))
+		return vs.w.write(vs.scratch[:3])
+	case v >= math.MinInt32:
+		vs.scratch[0] = codeInt32
+		binary.BigEndian.PutUint32(vs.scratch[1:], uint32(v))
+		return vs.w.write(vs.scratch[:5])
+	default:
+		vs.scratch[0] = codeInt64
+		binary.BigEndian.PutUint64(vs.scratch[1:], uint64(v))
+		return vs.w.write(vs.scratch[:9])
+	}
+}
+
+func (vs *Visitor) uint64(v uint64) error {
+	switch {
+	case v <= uint64(posFixIntMax):
+		return vs.writeByte(uint8(v))
+	case v <= math.MaxUint8:
+		vs.scratch[0], vs.scratch[1] = codeUint8, uint8(v)
+		return vs.w.write(vs.scratch[:2])
+	case v <= math.MaxUint16:
+		vs.scratch[0] = codeUint16
+		binary.BigEndian.PutUint16(vs.scratch[1:], uint16(v))
+		return vs.w.write(vs.scratch[:3])
+	case v <= math.MaxUint32:
+		vs.scratch[0] = codeUint32
+		binary.BigEndian.PutUint32(vs.scratch[1:], uint32(v))
+		return vs.w.write(vs.scratch[:5])
+	default:
+		vs.scratch[0] = codeUint64
+		binary.BigEndian.PutUint64(vs.scratch[1:], v)
+		return vs.w.write(vs.scratch[:9])
+	}
+}
+
+func (vs *Visitor) float32(f float32) error {
+	vs.scratch[0] = codeFloat32
+	binary.BigEndian.PutUint32(vs.scratch[1:], math.Float32bits(f))
+	return vs.w.write(vs.scratch[:5])
+}
+
+func (vs *Visitor) float64(f float64) error {
+	vs.scratch[0] = codeFloat64
+	binary.BigEndian.PutUint64(vs.scratch[1:], math.Float64bits(f))
+	return vs.w.write(vs.scratch[:9])
+}
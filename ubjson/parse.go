@@ -42,6 +42,9 @@ type Parser struct {
 	buffer  []byte
 	buffer0 [64]byte
 
+	// input offset of the next byte to be processed
+	offset int64
+
 	// internal parser state
 	marker    byte
 	valueType structform.BaseType
@@ -103,15 +106,29 @@ const (
 )
 
 var (
-	errUnknownMarker = errors.New("unknown ubjson marker")
-	errIncomplete    = errors.New("Incomplete UBJSON input")
-	errNegativeLen   = errors.New("negative length encountered")
-	errInvalidState  = errors.New("invalid state")
-	errMissingArrEnd = errors.New("missing ']'")
-	errMissingObjEnd = errors.New("missing '}'")
-	errMissingCount  = errors.New("missing count marker")
+	errUnknownMarker = newParseError("unknown ubjson marker")
+	errIncomplete    = newParseError("Incomplete UBJSON input")
+	errNegativeLen   = newParseError("negative length encountered")
+	errInvalidState  = newParseError("invalid state")
+	errMissingArrEnd = newParseError("missing ']'")
+	errMissingObjEnd = newParseError("missing '}'")
+	errMissingCount  = newParseError("missing count marker")
 )
 
+// parseError marks errors detected by the parser. Only parse errors are
+// reported as structform.SyntaxError, errors returned by the visitor are
+// passed through unchanged.
+type parseError struct {
+	err error
+}
+
+func newParseError(msg string) error {
+	return &parseError{errors.New(msg)}
+}
+
+func (e *parseError) Error() string { return e.err.Error() }
+func (e *parseError) Unwrap() error { return e.err }
+
 func ParseReader(in io.Reader, vs structform.Visitor) (int64, error) {
 	return NewParser(vs).ParseReader(in)
 }
@@ -169,7 +186,7 @@ func (p *Parser) finalize() error {
 		switch p.state.current.stateType {
 		case stArrayCount, stArrayTyped:
 			if p.length.current != 0 || p.state.current.stateStep != stCont {
-				return errMissingArrEnd
+				return p.syntaxError(errMissingArrEnd, 0)
 			}
 
 			err = p.visitor.OnArrayFinished()
@@ -177,13 +194,13 @@ func (p *Parser) finalize() error {
 			step := p.state.current.stateStep
 			l := p.length.current
 			if l != 0 || step != stFieldName {
-				return errMissingObjEnd
+				return p.syntaxError(errMissingObjEnd, 0)
 			}
 			err = p.visitor.OnObjectFinished()
 		}
 
 		if err != nil {
-			return err
+			return p.syntaxError(err, 0)
 		}
 		_, err = p.popState()
 	}
@@ -194,7 +211,7 @@ func (p *Parser) finalize() error {
 		st.stateType != stNext
 
 	if incomplete {
-		return errIncomplete
+		return p.syntaxError(errIncomplete, 0)
 	}
 	return nil
 }
@@ -225,11 +242,13 @@ func (p *Parser) feed(b []byte) error {
 func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 	var (
 		orig = b
+		last []byte
 		done bool
 		err  error
 	)
 
 	for {
+		last = b
 		b, done, err = p.execStep(b)
 		if done || err != nil {
 			break
@@ -239,7 +258,27 @@ func (p *Parser) feedUntil(b []byte) (int, bool, error) {
 			break
 		}
 	}
-	return len(orig) - len(b), done, err
+
+	if err != nil {
+		if b == nil {
+			b = last
+		}
+		consumed := len(orig) - len(b)
+		return consumed, false, p.syntaxError(err, consumed)
+	}
+
+	p.offset += int64(len(orig) - len(b))
+	return len(orig) - len(b), done, nil
+}
+
+// syntaxError annotates parse errors with the input offset after processing
+// another n bytes. Errors returned by the visitor are passed through
+// unchanged.
+func (p *Parser) syntaxError(err error, n int) error {
+	if _, ok := err.(*parseError); !ok {
+		return err // already annotated or returned by the visitor
+	}
+	return &structform.SyntaxError{Err: err, Offset: p.offset + int64(n)}
 }
 
 func (p *Parser) execStep(b []byte) ([]byte, bool, error) {
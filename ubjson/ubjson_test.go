@@ -68,3 +68,28 @@ func testEncParseConsistent(
 			}
 		})
 }
+
+func TestParseErrorOffset(t *testing.T) {
+	cases := map[string]struct {
+		in     string
+		offset int64
+	}{
+		"unknown marker":  {"[i\x01i\x02X]", 5},
+		"missing element": {"[#i\x02i\x01", 6},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var rec sftest.Recording
+			err := ParseString(test.in, &rec)
+			serr, ok := err.(*structform.SyntaxError)
+			if !ok {
+				t.Fatalf("expected syntax error, got: %v", err)
+			}
+			if serr.Offset != test.offset {
+				t.Errorf("expected offset %v, got %v", test.offset, serr.Offset)
+			}
+		})
+	}
+}
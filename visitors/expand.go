@@ -0,0 +1,378 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Expander converts dotted object keys into nested objects, such that
+// `{"a.b": 1}` is reported as `{"a": {"b": 1}}`. Fields sharing a prefix are
+// merged with each other and with sibling objects of the same name, e.g.
+// `{"a.b": 1, "a": {"c": 2}}` is reported as `{"a": {"b": 1, "c": 2}}`.
+// Objects in arrays are expanded as well, but never merged with each other.
+//
+// Dotted keys can only be resolved after all fields of an object are known.
+// Each object not nested in another object is buffered and reported once it
+// is finished. Values outside of objects are passed directly. The number of
+// values buffered is bounded by SetLimit. Processing fails if the limit is
+// exceeded.
+//
+// An error is returned if a key is used for a value and for an object (e.g.
+// `{"a": 1, "a.b": 2}`), if a key is used for multiple values, or if a dotted
+// key contains an empty segment (e.g. `a..b`).
+type Expander struct {
+	active structform.Visitor
+	limit  int
+
+	// number of values buffered
+	count int
+
+	// open objects and arrays being buffered
+	stack []*expandValue
+}
+
+type expandValue struct {
+	// obj holds the fields of objects
+	obj *expandObject
+
+	// elems holds the elements of arrays
+	array bool
+	elems []*expandValue
+
+	// value holds primitive values, using the go type reported to the
+	// Expander.
+	value interface{}
+
+	// key of the next field, if the value is an object being buffered
+	key string
+}
+
+type expandObject struct {
+	keys   []string
+	fields map[string]*expandValue
+}
+
+// DefaultExpandLimit is the default maximum number of values buffered by the
+// Expander.
+const DefaultExpandLimit = 1 << 16
+
+// NewExpander creates an Expander passing the expanded document to target.
+func NewExpander(target structform.Visitor) *Expander {
+	return &Expander{active: target, limit: DefaultExpandLimit}
+}
+
+// SetLimit sets the maximum number of values (including objects and arrays)
+// buffered per object. If limit is <= 0, the number of values is not bounded.
+func (e *Expander) SetLimit(limit int) {
+	e.limit = limit
+}
+
+// SetActive sets the visitor events are passed to.
+func (e *Expander) SetActive(a structform.Visitor) {
+	e.active = a
+}
+
+// Reset prepares the Expander for processing a new document.
+func (e *Expander) Reset() {
+	e.count = 0
+	for i := range e.stack {
+		e.stack[i] = nil
+	}
+	e.stack = e.stack[:0]
+}
+
+func (e *Expander) OnObjectStart(_ int, _ structform.BaseType) error {
+	v, err := e.container(false)
+	if err != nil {
+		return err
+	}
+	e.stack = append(e.stack, v)
+	return nil
+}
+
+func (e *Expander) OnArrayStart(l int, baseType structform.BaseType) error {
+	if len(e.stack) == 0 {
+		return e.active.OnArrayStart(l, baseType)
+	}
+
+	v, err := e.container(true)
+	if err != nil {
+		return err
+	}
+	e.stack = append(e.stack, v)
+	return nil
+}
+
+func (e *Expander) OnObjectFinished() error {
+	return e.finish(false)
+}
+
+func (e *Expander) OnArrayFinished() error {
+	if len(e.stack) == 0 {
+		return e.active.OnArrayFinished()
+	}
+	return e.finish(true)
+}
+
+func (e *Expander) OnKey(s string) error {
+	n := len(e.stack)
+	if n == 0 || e.stack[n-1].array {
+		return errors.New("expand: key outside of object")
+	}
+	e.stack[n-1].key = cloneString(s)
+	return nil
+}
+
+func (e *Expander) finish(array bool) error {
+	n := len(e.stack)
+	if n == 0 || e.stack[n-1].array != array {
+		return errors.New("expand: unbalanced object or array")
+	}
+
+	v := e.stack[n-1]
+	e.stack[n-1] = nil
+	e.stack = e.stack[:n-1]
+	if n > 1 {
+		return nil
+	}
+
+	e.count = 0
+	return e.replay(v)
+}
+
+// container creates or looks up the object or array the next events are
+// buffered into. Objects are merged with existing objects of the same name.
+func (e *Expander) container(array bool) (*expandValue, error) {
+	if err := e.reserve(); err != nil {
+		return nil, err
+	}
+
+	n := len(e.stack)
+	if n == 0 {
+		return &expandValue{obj: newExpandObject()}, nil
+	}
+
+	v := &expandValue{array: array}
+	if !array {
+		v.obj = newExpandObject()
+	}
+
+	parent := e.stack[n-1]
+	if parent.array {
+		parent.elems = append(parent.elems, v)
+		return v, nil
+	}
+	return parent.obj.insert(parent.key, v)
+}
+
+func (e *Expander) primitive(value interface{}) error {
+	n := len(e.stack)
+	if n == 0 {
+		return (&expandValue{value: value}).replayPrimitive(e.active)
+	}
+
+	if err := e.reserve(); err != nil {
+		return err
+	}
+
+	v := &expandValue{value: value}
+	parent := e.stack[n-1]
+	if parent.array {
+		parent.elems = append(parent.elems, v)
+		return nil
+	}
+	_, err := parent.obj.insert(parent.key, v)
+	return err
+}
+
+func (e *Expander) reserve() error {
+	e.count++
+	if e.limit > 0 && e.count > e.limit {
+		return fmt.Errorf("expand: object exceeds limit of %v buffered values", e.limit)
+	}
+	return nil
+}
+
+func newExpandObject() *expandObject {
+	return &expandObject{fields: map[string]*expandValue{}}
+}
+
+// insert adds v to the object using the dotted key. If v is an object and an
+// object with the same key exists already, the existing object is returned
+// for merging both.
+func (o *expandObject) insert(key string, v *expandValue) (*expandValue, error) {
+	segments := []string{key}
+	if strings.IndexByte(key, '.') >= 0 {
+		segments = strings.Split(key, ".")
+		for _, seg := range segments {
+			if seg == "" {
+				return nil, fmt.Errorf("expand: invalid key '%v': empty segment", key)
+			}
+		}
+	}
+
+	last := len(segments) - 1
+	for i, seg := range segments {
+		old, exists := o.fields[seg]
+		if !exists {
+			if i == last {
+				o.add(seg, v)
+				return v, nil
+			}
+
+			child := &expandValue{obj: newExpandObject()}
+			o.add(seg, child)
+			o = child.obj
+			continue
+		}
+
+		if old.obj == nil || (i == last && v.obj == nil) {
+			return nil, fmt.Errorf("expand: key '%v' conflicts with existing value at '%v'",
+				key, strings.Join(segments[:i+1], "."))
+		}
+		if i == last {
+			return old, nil
+		}
+		o = old.obj
+	}
+
+	// unreachable, segments is never empty
+	return v, nil
+}
+
+func (o *expandObject) add(key string, v *expandValue) {
+	o.keys = append(o.keys, key)
+	o.fields[key] = v
+}
+
+func (e *Expander) replay(v *expandValue) error {
+	switch {
+	case v.obj != nil:
+		if err := e.active.OnObjectStart(len(v.obj.keys), structform.AnyType); err != nil {
+			return err
+		}
+		for _, key := range v.obj.keys {
+			if err := e.active.OnKey(key); err != nil {
+				return err
+			}
+			if err := e.replay(v.obj.fields[key]); err != nil {
+				return err
+			}
+		}
+		return e.active.OnObjectFinished()
+
+	case v.array:
+		if err := e.active.OnArrayStart(len(v.elems), structform.AnyType); err != nil {
+			return err
+		}
+		for _, elem := range v.elems {
+			if err := e.replay(elem); err != nil {
+				return err
+			}
+		}
+		return e.active.OnArrayFinished()
+
+	default:
+		return v.replayPrimitive(e.active)
+	}
+}
+
+func (v *expandValue) replayPrimitive(to structform.Visitor) error {
+	switch x := v.value.(type) {
+	case nil:
+		return to.OnNil()
+	case bool:
+		return to.OnBool(x)
+	case string:
+		return to.OnString(x)
+	case int8:
+		return to.OnInt8(x)
+	case int16:
+		return to.OnInt16(x)
+	case int32:
+		return to.OnInt32(x)
+	case int64:
+		return to.OnInt64(x)
+	case int:
+		return to.OnInt(x)
+	case uint8:
+		return to.OnUint8(x)
+	case uint16:
+		return to.OnUint16(x)
+	case uint32:
+		return to.OnUint32(x)
+	case uint64:
+		return to.OnUint64(x)
+	case uint:
+		return to.OnUint(x)
+	case float32:
+		return to.OnFloat32(x)
+	case float64:
+		return to.OnFloat64(x)
+	default:
+		return fmt.Errorf("expand: unsupported value type %T", x)
+	}
+}
+
+// cloneString copies s, as strings reported by parsers may reference the
+// parser's input buffer.
+func cloneString(s string) string {
+	return string(append([]byte(nil), s...))
+}
+
+func (e *Expander) OnNil() error { return e.primitive(nil) }
+
+func (e *Expander) OnBool(b bool) error { return e.primitive(b) }
+
+func (e *Expander) OnString(s string) error {
+	if len(e.stack) == 0 {
+		return e.active.OnString(s)
+	}
+	return e.primitive(cloneString(s))
+}
+
+func (e *Expander) OnInt8(i int8) error { return e.primitive(i) }
+
+func (e *Expander) OnInt16(i int16) error { return e.primitive(i) }
+
+func (e *Expander) OnInt32(i int32) error { return e.primitive(i) }
+
+func (e *Expander) OnInt64(i int64) error { return e.primitive(i) }
+
+func (e *Expander) OnInt(i int) error { return e.primitive(i) }
+
+func (e *Expander) OnByte(b byte) error { return e.primitive(b) }
+
+func (e *Expander) OnUint8(u uint8) error { return e.primitive(u) }
+
+func (e *Expander) OnUint16(u uint16) error { return e.primitive(u) }
+
+func (e *Expander) OnUint32(u uint32) error { return e.primitive(u) }
+
+func (e *Expander) OnUint64(u uint64) error { return e.primitive(u) }
+
+func (e *Expander) OnUint(u uint) error { return e.primitive(u) }
+
+func (e *Expander) OnFloat32(f float32) error { return e.primitive(f) }
+
+func (e *Expander) OnFloat64(f float64) error { return e.primitive(f) }
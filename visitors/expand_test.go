@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+	"github.com/elastic/go-structform/sftest"
+)
+
+func TestExpander(t *testing.T) {
+	tests := map[string]struct {
+		in, want string
+	}{
+		"no dotted keys": {
+			in:   `{"a": 1, "b": {"c": [1, {"d": null}]}}`,
+			want: `{"a":1,"b":{"c":[1,{"d":null}]}}`,
+		},
+		"dotted keys": {
+			in:   `{"a.b.c": 1, "a.d": "x", "e": true}`,
+			want: `{"a":{"b":{"c":1},"d":"x"},"e":true}`,
+		},
+		"merge with object": {
+			in:   `{"a.b": 1, "a": {"c": 2, "d.e": 3}, "a.d.f": 4}`,
+			want: `{"a":{"b":1,"c":2,"d":{"e":3,"f":4}}}`,
+		},
+		"objects in arrays": {
+			in:   `{"a": [{"b.c": 1}, {"b.c": 2}]}`,
+			want: `{"a":[{"b":{"c":1}},{"b":{"c":2}}]}`,
+		},
+		"root array": {
+			in:   `[1, {"a.b": "x"}, [{"c.d": null}]]`,
+			want: `[1,{"a":{"b":"x"}},[{"c":{"d":null}}]]`,
+		},
+		"primitive": {
+			in:   `"a.b"`,
+			want: `"a.b"`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			e := NewExpander(json.NewVisitor(buf))
+			if err := json.ParseString(test.in, e); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestExpanderErrors(t *testing.T) {
+	tests := map[string]string{
+		"leaf and object":    `{"a": 1, "a.b": 2}`,
+		"object and leaf":    `{"a.b": 1, "a": 2}`,
+		"nested conflict":    `{"a": {"b": [1]}, "a.b.c": 2}`,
+		"duplicate key":      `{"a.b": 1, "a": {"b": 2}}`,
+		"empty segment":      `{"a..b": 1}`,
+		"trailing separator": `{"a.": 1}`,
+	}
+
+	for name, in := range tests {
+		in := in
+		t.Run(name, func(t *testing.T) {
+			e := NewExpander(NilVisitor())
+			assert.Error(t, json.ParseString(in, e))
+		})
+	}
+}
+
+func TestExpanderLimit(t *testing.T) {
+	e := NewExpander(NilVisitor())
+	e.SetLimit(3)
+	assert.NoError(t, json.ParseString(`[{"a": 1, "b": 2}, {"a": 1, "b": 2}]`, e))
+
+	e.Reset()
+	err := json.ParseString(`{"a": 1, "b": 2, "c": 3}`, e)
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "limit"), err.Error())
+	}
+}
+
+func TestFlattenExpandRoundtrip(t *testing.T) {
+	in := sftest.Recording(sftest.Obj(2, structform.AnyType,
+		"a", sftest.Obj(2, structform.AnyType,
+			"b", sftest.StringRec{"x"},
+			"c", sftest.Obj(1, structform.AnyType, "d", sftest.IntRec{1}),
+		),
+		"e", sftest.Arr(1, structform.AnyType, sftest.BoolRec{true}),
+	))
+
+	var flat sftest.Recording
+	if err := in.Replay(structform.EnsureExtVisitor(NewFlattener(&flat))); err != nil {
+		t.Fatal(err)
+	}
+	flat.Assert(t, sftest.Recording(sftest.Obj(-1, structform.AnyType,
+		"a.b", sftest.StringRec{"x"},
+		"a.c.d", sftest.IntRec{1},
+		"e", sftest.Arr(1, structform.AnyType, sftest.BoolRec{true}),
+	)))
+
+	var expanded sftest.Recording
+	if err := flat.Replay(structform.EnsureExtVisitor(NewExpander(&expanded))); err != nil {
+		t.Fatal(err)
+	}
+	expanded.Assert(t, in)
+}
@@ -0,0 +1,527 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Filter removes fields from a document while streaming. Fields are selected
+// by path patterns. Fields matching an exclude pattern are removed. If
+// include patterns are configured, only fields matching an include pattern
+// (and their parent objects) are kept. Exclude patterns take precedence over
+// include patterns.
+//
+// Patterns are object keys separated by '.'. The wildcard `*` matches any
+// key, and `**` matches any number of keys (e.g. `user.*`,
+// `http.request.headers.authorization`, `**.password`). Arrays are
+// transparent to patterns, such that a pattern applies to all objects in an
+// array.
+//
+// Objects and arrays with removed fields are reported with unknown length
+// (-1). Parent objects of included fields are only reported if at least one
+// field is kept. The top-level object or array is always reported.
+//
+// Filter implements structform.Visitor only. Events of the Extended Data
+// Model are converted into plain events by structform.EnsureExtVisitor.
+type Filter struct {
+	active  structform.Visitor
+	include []pathPattern
+	exclude []pathPattern
+
+	frames []filterFrame
+
+	// match states of the open objects and arrays. The state of frame i is
+	// stored at [i*len(patterns), (i+1)*len(patterns)).
+	inStates []uint64
+	exStates []uint64
+
+	// match state and mode of the value following the last key
+	inNext  []uint64
+	exNext  []uint64
+	next    filterMode
+	nextKey string
+
+	// depth of the removed object or array being skipped
+	skip int
+}
+
+// pathPattern holds the keys of a pattern. A pattern matching state is a
+// bitset, with bit i set if the first i keys have been matched.
+type pathPattern []string
+
+type filterMode uint8
+
+const (
+	// filterDrop removes the value
+	filterDrop filterMode = iota
+
+	// filterPartial removes the value, unless a nested value is included
+	filterPartial
+
+	// filterInclude keeps the value, but nested values might be excluded
+	filterInclude
+
+	// filterPass keeps the value and all nested values
+	filterPass
+)
+
+type filterFrame struct {
+	object bool
+	mode   filterMode
+
+	// key of the object or array in the parent object
+	key    string
+	hasKey bool
+
+	// declared length
+	len int
+	bt  structform.BaseType
+
+	// emitted is set once the start event has been passed to the active
+	// visitor
+	emitted bool
+}
+
+var errEmptyPattern = errors.New("empty path pattern")
+
+// NewFilter creates a Filter passing the selected fields to target.
+func NewFilter(target structform.Visitor, include, exclude []string) (*Filter, error) {
+	f := &Filter{active: target}
+
+	var err error
+	if f.include, err = parsePatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = parsePatterns(exclude); err != nil {
+		return nil, err
+	}
+
+	f.inNext = make([]uint64, len(f.include))
+	f.exNext = make([]uint64, len(f.exclude))
+	f.Reset()
+	return f, nil
+}
+
+func parsePatterns(patterns []string) ([]pathPattern, error) {
+	parsed := make([]pathPattern, len(patterns))
+	for i, pattern := range patterns {
+		keys := strings.Split(pattern, ".")
+		for _, key := range keys {
+			if key == "" {
+				return nil, fmt.Errorf("%v: '%v'", errEmptyPattern, pattern)
+			}
+		}
+		if len(keys) >= 64 {
+			return nil, fmt.Errorf("path pattern '%v' has too many keys", pattern)
+		}
+		parsed[i] = keys
+	}
+	return parsed, nil
+}
+
+// SetActive sets the visitor selected fields are passed to.
+func (f *Filter) SetActive(a structform.Visitor) {
+	f.active = a
+}
+
+// Reset prepares the Filter for processing a new document.
+func (f *Filter) Reset() {
+	f.frames = f.frames[:0]
+	f.inStates = f.inStates[:0]
+	f.exStates = f.exStates[:0]
+	f.skip = 0
+
+	for i, p := range f.include {
+		f.inNext[i] = p.closure(1)
+	}
+	for i, p := range f.exclude {
+		f.exNext[i] = p.closure(1)
+	}
+
+	// the top-level value is always kept
+	f.next = f.mode(f.inNext, f.exNext, false)
+	if f.next == filterDrop {
+		f.next = filterPartial
+	}
+}
+
+func (f *Filter) OnObjectStart(len int, baseType structform.BaseType) error {
+	return f.start(true, len, baseType)
+}
+
+func (f *Filter) OnArrayStart(len int, baseType structform.BaseType) error {
+	return f.start(false, len, baseType)
+}
+
+func (f *Filter) OnObjectFinished() error {
+	return f.finish(true)
+}
+
+func (f *Filter) OnArrayFinished() error {
+	return f.finish(false)
+}
+
+func (f *Filter) OnKey(s string) error {
+	if f.skip > 0 {
+		return nil
+	}
+	if len(f.frames) == 0 || !f.frames[len(f.frames)-1].object {
+		return errors.New("filter: key outside of object")
+	}
+
+	top := len(f.frames) - 1
+	switch f.frames[top].mode {
+	case filterPass:
+		f.next = filterPass
+	case filterInclude:
+		f.step(f.exclude, f.exStates[top*len(f.exclude):], f.exNext, s)
+		f.next = f.mode(nil, f.exNext, true)
+	case filterPartial:
+		f.step(f.include, f.inStates[top*len(f.include):], f.inNext, s)
+		f.step(f.exclude, f.exStates[top*len(f.exclude):], f.exNext, s)
+		f.next = f.mode(f.inNext, f.exNext, false)
+	}
+	f.nextKey = s
+	return nil
+}
+
+// value determines the mode of the next value. The state of array elements
+// is the state of the array.
+func (f *Filter) value() filterMode {
+	if len(f.frames) == 0 {
+		return f.next
+	}
+
+	top := len(f.frames) - 1
+	if f.frames[top].object {
+		return f.next
+	}
+
+	n := len(f.include)
+	copy(f.inNext, f.inStates[top*n:(top+1)*n])
+	n = len(f.exclude)
+	copy(f.exNext, f.exStates[top*n:(top+1)*n])
+	f.nextKey = ""
+	return f.frames[top].mode
+}
+
+// primitive checks if a primitive value is passed to the active visitor.
+func (f *Filter) primitive() (bool, error) {
+	if f.skip > 0 {
+		return false, nil
+	}
+
+	mode := f.value()
+	if len(f.frames) > 0 && mode < filterInclude {
+		return false, nil
+	}
+	if err := f.flush(); err != nil {
+		return false, err
+	}
+	return true, f.emitKey()
+}
+
+func (f *Filter) start(object bool, l int, bt structform.BaseType) error {
+	if f.skip > 0 {
+		f.skip++
+		return nil
+	}
+
+	mode := f.value()
+	if mode == filterDrop {
+		f.skip = 1
+		return nil
+	}
+
+	frame := filterFrame{object: object, mode: mode, len: l, bt: bt}
+	if len(f.frames) > 0 && f.frames[len(f.frames)-1].object {
+		frame.key, frame.hasKey = f.nextKey, true
+	}
+	f.frames = append(f.frames, frame)
+	f.inStates = append(f.inStates, f.inNext...)
+	f.exStates = append(f.exStates, f.exNext...)
+
+	if mode == filterPartial && len(f.frames) > 1 {
+		// wait for the first included value
+		return nil
+	}
+	return f.flush()
+}
+
+func (f *Filter) finish(object bool) error {
+	if f.skip > 0 {
+		f.skip--
+		return nil
+	}
+	if len(f.frames) == 0 || f.frames[len(f.frames)-1].object != object {
+		return errors.New("filter: unbalanced object or array")
+	}
+
+	top := len(f.frames) - 1
+	emitted := f.frames[top].emitted
+	f.frames = f.frames[:top]
+	f.inStates = f.inStates[:top*len(f.include)]
+	f.exStates = f.exStates[:top*len(f.exclude)]
+
+	if !emitted {
+		return nil
+	}
+	if object {
+		return f.active.OnObjectFinished()
+	}
+	return f.active.OnArrayFinished()
+}
+
+// flush passes the start events of all pending objects and arrays to the
+// active visitor.
+func (f *Filter) flush() error {
+	for i := range f.frames {
+		frame := &f.frames[i]
+		if frame.emitted {
+			continue
+		}
+
+		if frame.hasKey {
+			if err := f.active.OnKey(frame.key); err != nil {
+				return err
+			}
+		}
+
+		l := frame.len
+		if frame.mode != filterPass {
+			l = -1
+		}
+
+		var err error
+		if frame.object {
+			err = f.active.OnObjectStart(l, frame.bt)
+		} else {
+			err = f.active.OnArrayStart(l, frame.bt)
+		}
+		if err != nil {
+			return err
+		}
+		frame.emitted = true
+	}
+	return nil
+}
+
+// emitKey passes the key of the current value to the active visitor, if the
+// value is an object field.
+func (f *Filter) emitKey() error {
+	if len(f.frames) == 0 || !f.frames[len(f.frames)-1].object {
+		return nil
+	}
+	return f.active.OnKey(f.nextKey)
+}
+
+// mode selects the filter mode for a value, given the match states.
+func (f *Filter) mode(in, ex []uint64, included bool) filterMode {
+	partialEx := false
+	for i, p := range f.exclude {
+		if p.matches(ex[i]) {
+			return filterDrop
+		}
+		partialEx = partialEx || p.partial(ex[i])
+	}
+
+	if !included && len(f.include) > 0 {
+		partial := false
+		for i, p := range f.include {
+			if p.matches(in[i]) {
+				included = true
+				break
+			}
+			partial = partial || p.partial(in[i])
+		}
+
+		if !included {
+			if partial {
+				return filterPartial
+			}
+			return filterDrop
+		}
+	}
+
+	if partialEx {
+		return filterInclude
+	}
+	return filterPass
+}
+
+// step computes the match states after key.
+func (f *Filter) step(patterns []pathPattern, from, to []uint64, key string) {
+	for i, p := range patterns {
+		to[i] = p.step(from[i], key)
+	}
+}
+
+func (p pathPattern) step(state uint64, key string) uint64 {
+	var next uint64
+	for state != 0 {
+		pos := bits.TrailingZeros64(state)
+		state &= state - 1
+		if pos >= len(p) {
+			continue
+		}
+
+		switch p[pos] {
+		case "**":
+			next |= 1 << uint(pos)
+		case "*", key:
+			next |= 1 << uint(pos+1)
+		}
+	}
+	return p.closure(next)
+}
+
+// closure adds the states following a `**` matching no key.
+func (p pathPattern) closure(state uint64) uint64 {
+	for pos, key := range p {
+		if key == "**" && state&(1<<uint(pos)) != 0 {
+			state |= 1 << uint(pos+1)
+		}
+	}
+	return state
+}
+
+func (p pathPattern) matches(state uint64) bool {
+	return state&(1<<uint(len(p))) != 0
+}
+
+func (p pathPattern) partial(state uint64) bool {
+	return state&(1<<uint(len(p))-1) != 0
+}
+
+func (f *Filter) OnNil() error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnNil()
+}
+
+func (f *Filter) OnBool(b bool) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnBool(b)
+}
+
+func (f *Filter) OnString(s string) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnString(s)
+}
+
+func (f *Filter) OnInt8(i int8) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnInt8(i)
+}
+
+func (f *Filter) OnInt16(i int16) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnInt16(i)
+}
+
+func (f *Filter) OnInt32(i int32) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnInt32(i)
+}
+
+func (f *Filter) OnInt64(i int64) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnInt64(i)
+}
+
+func (f *Filter) OnInt(i int) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnInt(i)
+}
+
+func (f *Filter) OnByte(b byte) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnByte(b)
+}
+
+func (f *Filter) OnUint8(u uint8) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnUint8(u)
+}
+
+func (f *Filter) OnUint16(u uint16) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnUint16(u)
+}
+
+func (f *Filter) OnUint32(u uint32) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnUint32(u)
+}
+
+func (f *Filter) OnUint64(u uint64) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnUint64(u)
+}
+
+func (f *Filter) OnUint(u uint) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnUint(u)
+}
+
+func (f *Filter) OnFloat32(v float32) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnFloat32(v)
+}
+
+func (f *Filter) OnFloat64(v float64) error {
+	if ok, err := f.primitive(); !ok || err != nil {
+		return err
+	}
+	return f.active.OnFloat64(v)
+}
@@ -0,0 +1,148 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+	"github.com/elastic/go-structform/sftest"
+)
+
+func TestFilter(t *testing.T) {
+	doc := `{
+		"user": {"name": "bob", "password": "secret", "roles": ["a", "b"]},
+		"http": {"request": {"headers": {"authorization": "token", "accept": "*/*"}}},
+		"hosts": [{"name": "h1", "password": "x"}, {"name": "h2"}, 1],
+		"message": "hello"
+	}`
+
+	tests := map[string]struct {
+		include, exclude []string
+		want             string
+	}{
+		"no patterns": {
+			want: `{"user":{"name":"bob","password":"secret","roles":["a","b"]},"http":{"request":{"headers":{"authorization":"token","accept":"*/*"}}},"hosts":[{"name":"h1","password":"x"},{"name":"h2"},1],"message":"hello"}`,
+		},
+		"exclude field": {
+			exclude: []string{"http.request.headers.authorization"},
+			want:    `{"user":{"name":"bob","password":"secret","roles":["a","b"]},"http":{"request":{"headers":{"accept":"*/*"}}},"hosts":[{"name":"h1","password":"x"},{"name":"h2"},1],"message":"hello"}`,
+		},
+		"exclude any depth": {
+			exclude: []string{"**.password"},
+			want:    `{"user":{"name":"bob","roles":["a","b"]},"http":{"request":{"headers":{"authorization":"token","accept":"*/*"}}},"hosts":[{"name":"h1"},{"name":"h2"},1],"message":"hello"}`,
+		},
+		"exclude wildcard": {
+			exclude: []string{"user.*", "http"},
+			want:    `{"user":{},"hosts":[{"name":"h1","password":"x"},{"name":"h2"},1],"message":"hello"}`,
+		},
+		"include": {
+			include: []string{"user.name", "message"},
+			want:    `{"user":{"name":"bob"},"message":"hello"}`,
+		},
+		"include wildcard": {
+			include: []string{"user.*"},
+			exclude: []string{"user.password"},
+			want:    `{"user":{"name":"bob","roles":["a","b"]}}`,
+		},
+		"include in arrays": {
+			include: []string{"hosts.name"},
+			want:    `{"hosts":[{"name":"h1"},{"name":"h2"}]}`,
+		},
+		"include subtree": {
+			include: []string{"http.**"},
+			want:    `{"http":{"request":{"headers":{"authorization":"token","accept":"*/*"}}}}`,
+		},
+		"include without matches": {
+			include: []string{"user.missing", "unknown"},
+			want:    `{}`,
+		},
+		"exclude everything": {
+			exclude: []string{"**"},
+			want:    `{}`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			f, err := NewFilter(json.NewVisitor(buf), test.include, test.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.ParseString(doc, f); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestFilterLength(t *testing.T) {
+	in := sftest.Recording(sftest.Obj(2, structform.AnyType,
+		"a", sftest.Obj(2, structform.AnyType,
+			"b", sftest.StringRec{"x"},
+			"c", sftest.Arr(1, structform.AnyType, sftest.StringRec{"y"}),
+		),
+		"d", sftest.Obj(1, structform.AnyType, "e", sftest.IntRec{1}),
+	))
+
+	var rec sftest.Recording
+	f, err := NewFilter(&rec, nil, []string{"a.b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := in.Replay(structform.EnsureExtVisitor(f)); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.Assert(t, sftest.Recording(sftest.Obj(-1, structform.AnyType,
+		"a", sftest.Obj(-1, structform.AnyType,
+			"c", sftest.Arr(1, structform.AnyType, sftest.StringRec{"y"}),
+		),
+		"d", sftest.Obj(1, structform.AnyType, "e", sftest.IntRec{1}),
+	)))
+}
+
+func TestFilterReuse(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	f, err := NewFilter(json.NewVisitor(buf), []string{"a"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Error(t, json.ParseString(`{"a": [1, `, f))
+
+	f.Reset()
+	buf.Reset()
+	f.SetActive(json.NewVisitor(buf))
+	if assert.NoError(t, json.ParseString(`{"a": 1, "b": 2}`, f)) {
+		assert.Equal(t, `{"a":1}`, buf.String())
+	}
+}
+
+func TestFilterInvalidPattern(t *testing.T) {
+	_, err := NewFilter(NilVisitor(), []string{"a..b"}, nil)
+	assert.Error(t, err)
+}
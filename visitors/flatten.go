@@ -0,0 +1,287 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"errors"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Flattener converts nested objects into a single object with dotted keys,
+// such that `{"a": {"b": 1}}` is reported as `{"a.b": 1}`. Arrays and their
+// contents are passed unchanged. Empty objects are kept as values, because
+// they can not be represented by dotted keys.
+//
+// The Flattener does not buffer any values. The length of the flattened
+// object is unknown and reported as -1.
+type Flattener struct {
+	active structform.Visitor
+
+	// dotted key of the current value
+	key []byte
+
+	frames []flattenFrame
+
+	// depth of the array being passed unchanged
+	pass int
+}
+
+type flattenFrame struct {
+	// length of the key prefix of the object fields
+	prefix int
+
+	// number of fields reported
+	count int
+}
+
+// NewFlattener creates a Flattener passing the flattened document to target.
+func NewFlattener(target structform.Visitor) *Flattener {
+	return &Flattener{active: target}
+}
+
+// SetActive sets the visitor events are passed to.
+func (f *Flattener) SetActive(a structform.Visitor) {
+	f.active = a
+}
+
+// Reset prepares the Flattener for processing a new document.
+func (f *Flattener) Reset() {
+	f.key = f.key[:0]
+	f.frames = f.frames[:0]
+	f.pass = 0
+}
+
+func (f *Flattener) OnObjectStart(len int, baseType structform.BaseType) error {
+	if f.pass > 0 {
+		f.pass++
+		return f.active.OnObjectStart(len, baseType)
+	}
+
+	if f.enter() {
+		return f.active.OnObjectStart(-1, structform.AnyType)
+	}
+	return nil
+}
+
+// enter pushes a new object to be flattened. enter returns true for the
+// root object.
+func (f *Flattener) enter() bool {
+	if len(f.frames) == 0 {
+		f.frames = append(f.frames, flattenFrame{})
+		return true
+	}
+
+	f.key = append(f.key, '.')
+	f.frames = append(f.frames, flattenFrame{prefix: len(f.key)})
+	return false
+}
+
+func (f *Flattener) OnObjectFinished() error {
+	if f.pass > 0 {
+		f.pass--
+		return f.active.OnObjectFinished()
+	}
+
+	n := len(f.frames)
+	if n == 0 {
+		return errors.New("flatten: unbalanced object")
+	}
+
+	top := f.frames[n-1]
+	f.frames = f.frames[:n-1]
+	if n == 1 {
+		return f.active.OnObjectFinished()
+	}
+
+	parent := &f.frames[n-2]
+	parent.count++
+	if top.count > 0 {
+		return nil
+	}
+
+	// keep empty objects
+	f.key = f.key[:top.prefix-1]
+	if err := f.onKey(); err != nil {
+		return err
+	}
+	if err := f.active.OnObjectStart(0, structform.AnyType); err != nil {
+		return err
+	}
+	return f.active.OnObjectFinished()
+}
+
+func (f *Flattener) OnKey(s string) error {
+	if f.pass > 0 {
+		return f.active.OnKey(s)
+	}
+
+	n := len(f.frames)
+	if n == 0 {
+		return errors.New("flatten: key outside of object")
+	}
+	f.key = append(f.key[:f.frames[n-1].prefix], s...)
+	return nil
+}
+
+func (f *Flattener) OnArrayStart(len int, baseType structform.BaseType) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	f.pass++
+	return f.active.OnArrayStart(len, baseType)
+}
+
+func (f *Flattener) OnArrayFinished() error {
+	if f.pass == 0 {
+		return errors.New("flatten: unbalanced array")
+	}
+	f.pass--
+	return f.active.OnArrayFinished()
+}
+
+// value reports the dotted key of a value, if the value is a field of a
+// flattened object.
+func (f *Flattener) value() error {
+	if f.pass > 0 {
+		return nil
+	}
+
+	n := len(f.frames)
+	if n == 0 {
+		return nil
+	}
+	f.frames[n-1].count++
+	return f.onKey()
+}
+
+func (f *Flattener) onKey() error {
+	return f.active.OnKey(string(f.key))
+}
+
+func (f *Flattener) OnNil() error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnNil()
+}
+
+func (f *Flattener) OnBool(b bool) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnBool(b)
+}
+
+func (f *Flattener) OnString(s string) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnString(s)
+}
+
+func (f *Flattener) OnInt8(i int8) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnInt8(i)
+}
+
+func (f *Flattener) OnInt16(i int16) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnInt16(i)
+}
+
+func (f *Flattener) OnInt32(i int32) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnInt32(i)
+}
+
+func (f *Flattener) OnInt64(i int64) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnInt64(i)
+}
+
+func (f *Flattener) OnInt(i int) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnInt(i)
+}
+
+func (f *Flattener) OnByte(b byte) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnByte(b)
+}
+
+func (f *Flattener) OnUint8(u uint8) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnUint8(u)
+}
+
+func (f *Flattener) OnUint16(u uint16) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnUint16(u)
+}
+
+func (f *Flattener) OnUint32(u uint32) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnUint32(u)
+}
+
+func (f *Flattener) OnUint64(u uint64) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnUint64(u)
+}
+
+func (f *Flattener) OnUint(u uint) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnUint(u)
+}
+
+func (f *Flattener) OnFloat32(v float32) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnFloat32(v)
+}
+
+func (f *Flattener) OnFloat64(v float64) error {
+	if err := f.value(); err != nil {
+		return err
+	}
+	return f.active.OnFloat64(v)
+}
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-structform/json"
+)
+
+func TestFlattener(t *testing.T) {
+	tests := map[string]struct {
+		in, want string
+	}{
+		"flat": {
+			in:   `{"a": 1, "b": "x"}`,
+			want: `{"a":1,"b":"x"}`,
+		},
+		"nested": {
+			in:   `{"a": {"b": {"c": 1}, "d": "x"}, "e": true}`,
+			want: `{"a.b.c":1,"a.d":"x","e":true}`,
+		},
+		"arrays unchanged": {
+			in:   `{"a": {"b": [1, {"c": {"d": 2}}]}}`,
+			want: `{"a.b":[1,{"c":{"d":2}}]}`,
+		},
+		"empty objects": {
+			in:   `{"a": {}, "b": {"c": {}, "d": null}}`,
+			want: `{"a":{},"b.c":{},"b.d":null}`,
+		},
+		"root array": {
+			in:   `[{"a": {"b": 1}}]`,
+			want: `[{"a":{"b":1}}]`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			f := NewFlattener(json.NewVisitor(buf))
+			if err := json.ParseString(test.in, f); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
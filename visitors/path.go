@@ -0,0 +1,442 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"strconv"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// PathVisitor tracks the location of the current event in the document and
+// passes all events to the active visitor. The active visitor can query the
+// location via Path, JSONPointer and DottedPath while processing an event.
+//
+// While processing OnObjectStart, OnArrayStart, OnObjectFinished and
+// OnArrayFinished the location of the object or array is reported. OnKey
+// reports the location of the value following the key.
+type PathVisitor struct {
+	active structform.ExtVisitor
+	frames []pathFrame
+	path   []PathElem
+}
+
+// PathElem is an object key or array index in a document path.
+type PathElem struct {
+	Key string
+
+	// Index is the array index, or -1 if the element is an object key.
+	Index int
+}
+
+type pathFrame struct {
+	object bool
+	key    string
+	idx    int
+
+	// set is true after the first key or array element has been reported
+	set bool
+}
+
+// NewPathVisitor creates a PathVisitor passing events to target. The target
+// can be nil and configured later via SetActive, so the target can hold a
+// reference to the PathVisitor.
+func NewPathVisitor(target structform.Visitor) *PathVisitor {
+	v := &PathVisitor{}
+	if target != nil {
+		v.SetActive(target)
+	}
+	return v
+}
+
+// SetActive sets the visitor events are passed to.
+func (v *PathVisitor) SetActive(a structform.Visitor) {
+	v.active = structform.EnsureExtVisitor(a)
+}
+
+// Reset clears the location, for processing a new document.
+func (v *PathVisitor) Reset() {
+	v.frames = v.frames[:0]
+}
+
+// Depth returns the number of open objects and arrays.
+func (v *PathVisitor) Depth() int {
+	return len(v.frames)
+}
+
+// Path returns the elements of the current location. The slice is only valid
+// until the next event is processed.
+func (v *PathVisitor) Path() []PathElem {
+	v.path = v.path[:0]
+	for i := range v.frames {
+		f := &v.frames[i]
+		if !f.set {
+			break
+		}
+
+		if f.object {
+			v.path = append(v.path, PathElem{Key: f.key, Index: -1})
+		} else {
+			v.path = append(v.path, PathElem{Index: f.idx})
+		}
+	}
+	return v.path
+}
+
+// JSONPointer returns the current location as RFC 6901 JSON Pointer (e.g.
+// `/outputs/2/hosts`). The empty string is returned at the top-level.
+func (v *PathVisitor) JSONPointer() string {
+	var sb strings.Builder
+	for _, elem := range v.Path() {
+		sb.WriteByte('/')
+		if elem.Index >= 0 {
+			sb.WriteString(strconv.Itoa(elem.Index))
+		} else {
+			sb.WriteString(jsonPointerEscaper.Replace(elem.Key))
+		}
+	}
+	return sb.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// DottedPath returns the current location with object keys separated by '.'
+// and array indices given in brackets (e.g. `outputs[2].hosts`).
+func (v *PathVisitor) DottedPath() string {
+	var sb strings.Builder
+	for i, elem := range v.Path() {
+		if elem.Index >= 0 {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(elem.Index))
+			sb.WriteByte(']')
+			continue
+		}
+
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(elem.Key)
+	}
+	return sb.String()
+}
+
+// String returns the object key or the array index.
+func (e PathElem) String() string {
+	if e.Index >= 0 {
+		return strconv.Itoa(e.Index)
+	}
+	return e.Key
+}
+
+func (v *PathVisitor) OnObjectStart(len int, baseType structform.BaseType) error {
+	v.value()
+	if err := v.active.OnObjectStart(len, baseType); err != nil {
+		return err
+	}
+	v.frames = append(v.frames, pathFrame{object: true})
+	return nil
+}
+
+func (v *PathVisitor) OnObjectFinished() error {
+	v.leave()
+	return v.active.OnObjectFinished()
+}
+
+func (v *PathVisitor) OnKey(s string) error {
+	v.key(s)
+	return v.active.OnKey(s)
+}
+
+func (v *PathVisitor) OnKeyRef(s []byte) error {
+	v.key(string(s))
+	return v.active.OnKeyRef(s)
+}
+
+func (v *PathVisitor) OnArrayStart(len int, baseType structform.BaseType) error {
+	v.value()
+	if err := v.active.OnArrayStart(len, baseType); err != nil {
+		return err
+	}
+	v.frames = append(v.frames, pathFrame{idx: -1})
+	return nil
+}
+
+func (v *PathVisitor) OnArrayFinished() error {
+	v.leave()
+	return v.active.OnArrayFinished()
+}
+
+// value advances the array index, if the value is an array element.
+func (v *PathVisitor) value() {
+	if len(v.frames) == 0 {
+		return
+	}
+
+	top := &v.frames[len(v.frames)-1]
+	if !top.object {
+		top.idx++
+		top.set = true
+	}
+}
+
+func (v *PathVisitor) key(s string) {
+	if len(v.frames) == 0 {
+		return
+	}
+
+	top := &v.frames[len(v.frames)-1]
+	top.key = s
+	top.set = true
+}
+
+func (v *PathVisitor) leave() {
+	if len(v.frames) > 0 {
+		v.frames = v.frames[:len(v.frames)-1]
+	}
+}
+
+func (v *PathVisitor) OnNil() error {
+	v.value()
+	return v.active.OnNil()
+}
+
+func (v *PathVisitor) OnBool(b bool) error {
+	v.value()
+	return v.active.OnBool(b)
+}
+
+func (v *PathVisitor) OnString(s string) error {
+	v.value()
+	return v.active.OnString(s)
+}
+
+func (v *PathVisitor) OnStringRef(s []byte) error {
+	v.value()
+	return v.active.OnStringRef(s)
+}
+
+func (v *PathVisitor) OnInt8(i int8) error {
+	v.value()
+	return v.active.OnInt8(i)
+}
+
+func (v *PathVisitor) OnInt16(i int16) error {
+	v.value()
+	return v.active.OnInt16(i)
+}
+
+func (v *PathVisitor) OnInt32(i int32) error {
+	v.value()
+	return v.active.OnInt32(i)
+}
+
+func (v *PathVisitor) OnInt64(i int64) error {
+	v.value()
+	return v.active.OnInt64(i)
+}
+
+func (v *PathVisitor) OnInt(i int) error {
+	v.value()
+	return v.active.OnInt(i)
+}
+
+func (v *PathVisitor) OnByte(b byte) error {
+	v.value()
+	return v.active.OnByte(b)
+}
+
+func (v *PathVisitor) OnUint8(u uint8) error {
+	v.value()
+	return v.active.OnUint8(u)
+}
+
+func (v *PathVisitor) OnUint16(u uint16) error {
+	v.value()
+	return v.active.OnUint16(u)
+}
+
+func (v *PathVisitor) OnUint32(u uint32) error {
+	v.value()
+	return v.active.OnUint32(u)
+}
+
+func (v *PathVisitor) OnUint64(u uint64) error {
+	v.value()
+	return v.active.OnUint64(u)
+}
+
+func (v *PathVisitor) OnUint(u uint) error {
+	v.value()
+	return v.active.OnUint(u)
+}
+
+func (v *PathVisitor) OnFloat32(f float32) error {
+	v.value()
+	return v.active.OnFloat32(f)
+}
+
+func (v *PathVisitor) OnFloat64(f float64) error {
+	v.value()
+	return v.active.OnFloat64(f)
+}
+
+func (v *PathVisitor) OnBoolArray(a []bool) error {
+	v.value()
+	return v.active.OnBoolArray(a)
+}
+
+func (v *PathVisitor) OnStringArray(a []string) error {
+	v.value()
+	return v.active.OnStringArray(a)
+}
+
+func (v *PathVisitor) OnInt8Array(a []int8) error {
+	v.value()
+	return v.active.OnInt8Array(a)
+}
+
+func (v *PathVisitor) OnInt16Array(a []int16) error {
+	v.value()
+	return v.active.OnInt16Array(a)
+}
+
+func (v *PathVisitor) OnInt32Array(a []int32) error {
+	v.value()
+	return v.active.OnInt32Array(a)
+}
+
+func (v *PathVisitor) OnInt64Array(a []int64) error {
+	v.value()
+	return v.active.OnInt64Array(a)
+}
+
+func (v *PathVisitor) OnIntArray(a []int) error {
+	v.value()
+	return v.active.OnIntArray(a)
+}
+
+func (v *PathVisitor) OnBytes(b []byte) error {
+	v.value()
+	return v.active.OnBytes(b)
+}
+
+func (v *PathVisitor) OnUint8Array(a []uint8) error {
+	v.value()
+	return v.active.OnUint8Array(a)
+}
+
+func (v *PathVisitor) OnUint16Array(a []uint16) error {
+	v.value()
+	return v.active.OnUint16Array(a)
+}
+
+func (v *PathVisitor) OnUint32Array(a []uint32) error {
+	v.value()
+	return v.active.OnUint32Array(a)
+}
+
+func (v *PathVisitor) OnUint64Array(a []uint64) error {
+	v.value()
+	return v.active.OnUint64Array(a)
+}
+
+func (v *PathVisitor) OnUintArray(a []uint) error {
+	v.value()
+	return v.active.OnUintArray(a)
+}
+
+func (v *PathVisitor) OnFloat32Array(a []float32) error {
+	v.value()
+	return v.active.OnFloat32Array(a)
+}
+
+func (v *PathVisitor) OnFloat64Array(a []float64) error {
+	v.value()
+	return v.active.OnFloat64Array(a)
+}
+
+func (v *PathVisitor) OnBoolObject(m map[string]bool) error {
+	v.value()
+	return v.active.OnBoolObject(m)
+}
+
+func (v *PathVisitor) OnStringObject(m map[string]string) error {
+	v.value()
+	return v.active.OnStringObject(m)
+}
+
+func (v *PathVisitor) OnInt8Object(m map[string]int8) error {
+	v.value()
+	return v.active.OnInt8Object(m)
+}
+
+func (v *PathVisitor) OnInt16Object(m map[string]int16) error {
+	v.value()
+	return v.active.OnInt16Object(m)
+}
+
+func (v *PathVisitor) OnInt32Object(m map[string]int32) error {
+	v.value()
+	return v.active.OnInt32Object(m)
+}
+
+func (v *PathVisitor) OnInt64Object(m map[string]int64) error {
+	v.value()
+	return v.active.OnInt64Object(m)
+}
+
+func (v *PathVisitor) OnIntObject(m map[string]int) error {
+	v.value()
+	return v.active.OnIntObject(m)
+}
+
+func (v *PathVisitor) OnUint8Object(m map[string]uint8) error {
+	v.value()
+	return v.active.OnUint8Object(m)
+}
+
+func (v *PathVisitor) OnUint16Object(m map[string]uint16) error {
+	v.value()
+	return v.active.OnUint16Object(m)
+}
+
+func (v *PathVisitor) OnUint32Object(m map[string]uint32) error {
+	v.value()
+	return v.active.OnUint32Object(m)
+}
+
+func (v *PathVisitor) OnUint64Object(m map[string]uint64) error {
+	v.value()
+	return v.active.OnUint64Object(m)
+}
+
+func (v *PathVisitor) OnUintObject(m map[string]uint) error {
+	v.value()
+	return v.active.OnUintObject(m)
+}
+
+func (v *PathVisitor) OnFloat32Object(m map[string]float32) error {
+	v.value()
+	return v.active.OnFloat32Object(m)
+}
+
+func (v *PathVisitor) OnFloat64Object(m map[string]float64) error {
+	v.value()
+	return v.active.OnFloat64Object(m)
+}
@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+)
+
+// pathRecorder records the location of each event reported by a
+// PathVisitor.
+type pathRecorder struct {
+	structform.Visitor
+	path     *PathVisitor
+	pointers []string
+	dotted   []string
+}
+
+func newPathRecorder() *pathRecorder {
+	r := &pathRecorder{Visitor: NilVisitor()}
+	r.path = NewPathVisitor(nil)
+	r.path.SetActive(r)
+	return r
+}
+
+func (r *pathRecorder) record() error {
+	r.pointers = append(r.pointers, r.path.JSONPointer())
+	r.dotted = append(r.dotted, r.path.DottedPath())
+	return nil
+}
+
+func (r *pathRecorder) OnString(s string) error { return r.record() }
+func (r *pathRecorder) OnInt64(i int64) error   { return r.record() }
+func (r *pathRecorder) OnObjectFinished() error { return r.record() }
+
+func TestPathVisitor(t *testing.T) {
+	r := newPathRecorder()
+	in := `{"a": {"b": [1, {"c/d": "x", "e~f": [[2]]}], "g": 3}, "h": "y"}`
+	if err := json.ParseString(in, r.path); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{
+		"/a/b/0",
+		"/a/b/1/c~1d",
+		"/a/b/1/e~0f/0/0",
+		"/a/b/1",
+		"/a/g",
+		"/a",
+		"/h",
+		"",
+	}, r.pointers)
+	assert.Equal(t, []string{
+		"a.b[0]",
+		"a.b[1].c/d",
+		"a.b[1].e~f[0][0]",
+		"a.b[1]",
+		"a.g",
+		"a",
+		"h",
+		"",
+	}, r.dotted)
+	assert.Equal(t, 0, r.path.Depth())
+}
+
+func TestPathVisitorPath(t *testing.T) {
+	v := NewPathVisitor(NilVisitor())
+	assert.NoError(t, v.OnArrayStart(-1, structform.AnyType))
+	assert.Empty(t, v.Path())
+
+	assert.NoError(t, v.OnInt(1))
+	assert.NoError(t, v.OnObjectStart(-1, structform.AnyType))
+	assert.Equal(t, []PathElem{{Index: 1}}, v.Path())
+
+	assert.NoError(t, v.OnKeyRef([]byte("key")))
+	assert.Equal(t, []PathElem{{Index: 1}, {Key: "key", Index: -1}}, v.Path())
+	assert.Equal(t, 2, v.Depth())
+
+	v.Reset()
+	assert.Empty(t, v.Path())
+}
@@ -0,0 +1,334 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Redactor replaces values selected by RedactRules while streaming. Object
+// keys, objects and arrays are passed unchanged, such that the document
+// structure is preserved. If an object or array is selected, all primitive
+// values in the object or array are replaced.
+//
+// Selected values are converted to strings (numbers are formatted in base
+// 10, booleans as `true` or `false`) and passed to the Redaction of the
+// first matching rule. The result is reported as string. Nil values are not
+// replaced.
+//
+// Redactor implements structform.Visitor only. Events of the Extended Data
+// Model are converted into plain events by structform.EnsureExtVisitor.
+type Redactor struct {
+	active   structform.Visitor
+	patterns []pathPattern
+
+	// rules holds the Redaction of each pattern
+	rules []Redaction
+
+	frames []redactFrame
+
+	// match states of the open objects and arrays. The state of frame i is
+	// stored at [i*len(patterns), (i+1)*len(patterns)).
+	states []uint64
+
+	// match state and redaction of the value following the last key
+	next       []uint64
+	nextRedact Redaction
+}
+
+// RedactRule selects the values to be replaced by Redaction.
+type RedactRule struct {
+	// Paths selects values by path pattern. See Filter for the pattern
+	// syntax.
+	Paths []string
+
+	// Keys selects the values of object fields with the given names at any
+	// depth.
+	Keys []string
+
+	Redaction Redaction
+}
+
+// Redaction computes the replacement for a value.
+type Redaction func(value string) string
+
+type redactFrame struct {
+	object bool
+
+	// redact is the Redaction applied to all values in the object or array,
+	// if the object or array has been selected.
+	redact Redaction
+}
+
+var errMissingRedaction = errors.New("redact rule without redaction")
+
+// NewRedactor creates a Redactor passing all values to target, replacing
+// the values selected by rules.
+func NewRedactor(target structform.Visitor, rules ...RedactRule) (*Redactor, error) {
+	r := &Redactor{active: target}
+
+	for _, rule := range rules {
+		if rule.Redaction == nil {
+			return nil, errMissingRedaction
+		}
+
+		patterns, err := parsePatterns(rule.Paths)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range rule.Keys {
+			patterns = append(patterns, pathPattern{"**", key})
+		}
+
+		for _, p := range patterns {
+			r.patterns = append(r.patterns, p)
+			r.rules = append(r.rules, rule.Redaction)
+		}
+	}
+
+	r.next = make([]uint64, len(r.patterns))
+	r.Reset()
+	return r, nil
+}
+
+// RedactMask replaces values with mask.
+func RedactMask(mask string) Redaction {
+	return func(string) string { return mask }
+}
+
+// RedactPrefix keeps the first n characters of a value only.
+func RedactPrefix(n int) Redaction {
+	return func(value string) string {
+		i := 0
+		for pos := range value {
+			if i == n {
+				return value[:pos]
+			}
+			i++
+		}
+		return value
+	}
+}
+
+// RedactHMAC replaces values with the hex encoded HMAC-SHA256 of the value,
+// using key as secret. Equal values produce equal hashes, such that redacted
+// values can still be correlated.
+func RedactHMAC(key []byte) Redaction {
+	key = append([]byte(nil), key...)
+	return func(value string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+// SetActive sets the visitor events are passed to.
+func (r *Redactor) SetActive(a structform.Visitor) {
+	r.active = a
+}
+
+// Reset prepares the Redactor for processing a new document.
+func (r *Redactor) Reset() {
+	r.frames = r.frames[:0]
+	r.states = r.states[:0]
+	for i, p := range r.patterns {
+		r.next[i] = p.closure(1)
+	}
+	r.nextRedact = nil
+}
+
+func (r *Redactor) OnObjectStart(len int, baseType structform.BaseType) error {
+	r.enter(true)
+	return r.active.OnObjectStart(len, baseType)
+}
+
+func (r *Redactor) OnArrayStart(len int, baseType structform.BaseType) error {
+	r.enter(false)
+	return r.active.OnArrayStart(len, baseType)
+}
+
+func (r *Redactor) OnObjectFinished() error {
+	r.leave()
+	return r.active.OnObjectFinished()
+}
+
+func (r *Redactor) OnArrayFinished() error {
+	r.leave()
+	return r.active.OnArrayFinished()
+}
+
+func (r *Redactor) OnKey(s string) error {
+	if n := len(r.frames); n > 0 {
+		top := n - 1
+		if redact := r.frames[top].redact; redact != nil {
+			r.nextRedact = redact
+		} else {
+			r.nextRedact = nil
+			from := r.states[top*len(r.patterns):]
+			for i, p := range r.patterns {
+				r.next[i] = p.step(from[i], s)
+				if r.nextRedact == nil && p.matches(r.next[i]) {
+					r.nextRedact = r.rules[i]
+				}
+			}
+		}
+	}
+	return r.active.OnKey(s)
+}
+
+// redaction returns the Redaction for the current value, or nil if the value
+// is not selected.
+func (r *Redactor) redaction() Redaction {
+	n := len(r.frames)
+	if n == 0 || r.frames[n-1].object {
+		return r.nextRedact
+	}
+
+	// array elements inherit the state of the array
+	top := n - 1
+	copy(r.next, r.states[top*len(r.patterns):n*len(r.patterns)])
+	return r.frames[top].redact
+}
+
+func (r *Redactor) enter(object bool) {
+	redact := r.redaction()
+	r.frames = append(r.frames, redactFrame{object: object, redact: redact})
+	r.states = append(r.states, r.next...)
+}
+
+func (r *Redactor) leave() {
+	if n := len(r.frames); n > 0 {
+		r.frames = r.frames[:n-1]
+		r.states = r.states[:(n-1)*len(r.patterns)]
+	}
+}
+
+func (r *Redactor) OnNil() error {
+	return r.active.OnNil()
+}
+
+func (r *Redactor) OnBool(b bool) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatBool(b)))
+	}
+	return r.active.OnBool(b)
+}
+
+func (r *Redactor) OnString(s string) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(s))
+	}
+	return r.active.OnString(s)
+}
+
+func (r *Redactor) OnFloat32(f float32) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatFloat(float64(f), 'g', -1, 32)))
+	}
+	return r.active.OnFloat32(f)
+}
+
+func (r *Redactor) OnFloat64(f float64) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatFloat(f, 'g', -1, 64)))
+	}
+	return r.active.OnFloat64(f)
+}
+
+func (r *Redactor) OnInt8(i int8) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatInt(int64(i), 10)))
+	}
+	return r.active.OnInt8(i)
+}
+
+func (r *Redactor) OnInt16(i int16) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatInt(int64(i), 10)))
+	}
+	return r.active.OnInt16(i)
+}
+
+func (r *Redactor) OnInt32(i int32) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatInt(int64(i), 10)))
+	}
+	return r.active.OnInt32(i)
+}
+
+func (r *Redactor) OnInt64(i int64) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatInt(i, 10)))
+	}
+	return r.active.OnInt64(i)
+}
+
+func (r *Redactor) OnInt(i int) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatInt(int64(i), 10)))
+	}
+	return r.active.OnInt(i)
+}
+
+func (r *Redactor) OnByte(b byte) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(uint64(b), 10)))
+	}
+	return r.active.OnByte(b)
+}
+
+func (r *Redactor) OnUint8(u uint8) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(uint64(u), 10)))
+	}
+	return r.active.OnUint8(u)
+}
+
+func (r *Redactor) OnUint16(u uint16) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(uint64(u), 10)))
+	}
+	return r.active.OnUint16(u)
+}
+
+func (r *Redactor) OnUint32(u uint32) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(uint64(u), 10)))
+	}
+	return r.active.OnUint32(u)
+}
+
+func (r *Redactor) OnUint64(u uint64) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(u, 10)))
+	}
+	return r.active.OnUint64(u)
+}
+
+func (r *Redactor) OnUint(u uint) error {
+	if redact := r.redaction(); redact != nil {
+		return r.active.OnString(redact(strconv.FormatUint(uint64(u), 10)))
+	}
+	return r.active.OnUint(u)
+}
@@ -0,0 +1,124 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/go-structform/json"
+)
+
+func TestRedactor(t *testing.T) {
+	doc := `{
+		"user": {"id": "u-12345", "email": "bob@example.com", "age": 42, "admin": true},
+		"hosts": [{"name": "h1", "password": "x"}, {"name": "h2", "password": null}],
+		"tokens": ["abc", {"value": 1.5}],
+		"message": "hello"
+	}`
+
+	tests := map[string]struct {
+		rules []RedactRule
+		want  string
+	}{
+		"no rules": {
+			want: `{"user":{"id":"u-12345","email":"bob@example.com","age":42,"admin":true},"hosts":[{"name":"h1","password":"x"},{"name":"h2","password":null}],"tokens":["abc",{"value":1.5}],"message":"hello"}`,
+		},
+		"mask keys": {
+			rules: []RedactRule{{Keys: []string{"password"}, Redaction: RedactMask("***")}},
+			want:  `{"user":{"id":"u-12345","email":"bob@example.com","age":42,"admin":true},"hosts":[{"name":"h1","password":"***"},{"name":"h2","password":null}],"tokens":["abc",{"value":1.5}],"message":"hello"}`,
+		},
+		"mask paths": {
+			rules: []RedactRule{{Paths: []string{"user.*"}, Redaction: RedactMask("-")}},
+			want:  `{"user":{"id":"-","email":"-","age":"-","admin":"-"},"hosts":[{"name":"h1","password":"x"},{"name":"h2","password":null}],"tokens":["abc",{"value":1.5}],"message":"hello"}`,
+		},
+		"mask subtree": {
+			rules: []RedactRule{{Paths: []string{"tokens"}, Redaction: RedactMask("-")}},
+			want:  `{"user":{"id":"u-12345","email":"bob@example.com","age":42,"admin":true},"hosts":[{"name":"h1","password":"x"},{"name":"h2","password":null}],"tokens":["-",{"value":"-"}],"message":"hello"}`,
+		},
+		"prefix": {
+			rules: []RedactRule{{Paths: []string{"user.email", "user.age"}, Redaction: RedactPrefix(3)}},
+			want:  `{"user":{"id":"u-12345","email":"bob","age":"42","admin":true},"hosts":[{"name":"h1","password":"x"},{"name":"h2","password":null}],"tokens":["abc",{"value":1.5}],"message":"hello"}`,
+		},
+		"first rule wins": {
+			rules: []RedactRule{
+				{Paths: []string{"user.id"}, Redaction: RedactPrefix(2)},
+				{Keys: []string{"id", "name"}, Redaction: RedactMask("*")},
+			},
+			want: `{"user":{"id":"u-","email":"bob@example.com","age":42,"admin":true},"hosts":[{"name":"*","password":"x"},{"name":"*","password":null}],"tokens":["abc",{"value":1.5}],"message":"hello"}`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			r, err := NewRedactor(json.NewVisitor(buf), test.rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.ParseString(doc, r); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, buf.String())
+		})
+	}
+}
+
+func TestRedactHMAC(t *testing.T) {
+	key := []byte("secret")
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("u-12345"))
+	hash := hex.EncodeToString(mac.Sum(nil))
+
+	buf := bytes.NewBuffer(nil)
+	r, err := NewRedactor(json.NewVisitor(buf), RedactRule{
+		Keys:      []string{"user_id"},
+		Redaction: RedactHMAC(key),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := `[{"user_id": "u-12345"}, {"user_id": "u-12345", "n": 1}]`
+	if assert.NoError(t, json.ParseString(in, r)) {
+		want := `[{"user_id":"` + hash + `"},{"user_id":"` + hash + `","n":1}]`
+		assert.Equal(t, want, buf.String())
+	}
+}
+
+func TestRedactPrefix(t *testing.T) {
+	redact := RedactPrefix(2)
+	assert.Equal(t, "äö", redact("äöü"))
+	assert.Equal(t, "a", redact("a"))
+	assert.Equal(t, "", RedactPrefix(0)("abc"))
+}
+
+func TestRedactorInvalidRule(t *testing.T) {
+	_, err := NewRedactor(NilVisitor(), RedactRule{Keys: []string{"a"}})
+	assert.Error(t, err)
+
+	_, err = NewRedactor(NilVisitor(), RedactRule{Paths: []string{"a..b"}, Redaction: RedactMask("")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,513 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"fmt"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Validator wraps a visitor and checks all events follow the protocol
+// documented by structform.Visitor. Invalid events are not passed to the
+// target visitor, but return an error naming the faulty call.
+//
+// The Validator checks:
+//   - OnKey is called directly inside objects only,
+//   - exactly one value follows each key,
+//   - objects and arrays are closed in order,
+//   - the number of fields and elements matches the declared length, if the
+//     length is not -1,
+//   - one value is reported per document.
+type Validator struct {
+	active structform.ExtVisitor
+	stack  []validatorFrame
+	done   bool
+}
+
+type validatorFrame struct {
+	object bool
+
+	// declared length, -1 if unknown
+	len int
+
+	// number of values reported
+	count int
+
+	// key has been reported, waiting for value
+	key bool
+}
+
+// NewValidator creates a Validator passing all valid events to target.
+func NewValidator(target structform.Visitor) *Validator {
+	return &Validator{active: structform.EnsureExtVisitor(target)}
+}
+
+// Reset prepares the Validator for validating a new document.
+func (v *Validator) Reset() {
+	v.stack = v.stack[:0]
+	v.done = false
+}
+
+// Done checks if a complete document has been reported.
+func (v *Validator) Done() bool {
+	return v.done
+}
+
+func (v *Validator) OnObjectStart(len int, baseType structform.BaseType) error {
+	if err := v.value("OnObjectStart"); err != nil {
+		return err
+	}
+	if err := v.active.OnObjectStart(len, baseType); err != nil {
+		return err
+	}
+	v.stack = append(v.stack, validatorFrame{object: true, len: len})
+	return nil
+}
+
+func (v *Validator) OnObjectFinished() error {
+	if err := v.finish("OnObjectFinished", true); err != nil {
+		return err
+	}
+	return v.active.OnObjectFinished()
+}
+
+func (v *Validator) OnKey(s string) error {
+	if err := v.key("OnKey", s); err != nil {
+		return err
+	}
+	return v.active.OnKey(s)
+}
+
+func (v *Validator) OnKeyRef(s []byte) error {
+	if err := v.key("OnKeyRef", string(s)); err != nil {
+		return err
+	}
+	return v.active.OnKeyRef(s)
+}
+
+func (v *Validator) OnArrayStart(len int, baseType structform.BaseType) error {
+	if err := v.value("OnArrayStart"); err != nil {
+		return err
+	}
+	if err := v.active.OnArrayStart(len, baseType); err != nil {
+		return err
+	}
+	v.stack = append(v.stack, validatorFrame{len: len})
+	return nil
+}
+
+func (v *Validator) OnArrayFinished() error {
+	if err := v.finish("OnArrayFinished", false); err != nil {
+		return err
+	}
+	return v.active.OnArrayFinished()
+}
+
+// value checks a value is allowed in the current state and records the
+// value.
+func (v *Validator) value(event string) error {
+	if len(v.stack) == 0 {
+		if v.done {
+			return fmt.Errorf("invalid %v: document already complete", event)
+		}
+		if event != "OnObjectStart" && event != "OnArrayStart" {
+			v.done = true
+		}
+		return nil
+	}
+
+	top := &v.stack[len(v.stack)-1]
+	if top.object {
+		if !top.key {
+			return fmt.Errorf("invalid %v: object value without key", event)
+		}
+		top.key = false
+	}
+	if top.len >= 0 && top.count >= top.len {
+		return fmt.Errorf("invalid %v: %v exceeds declared length %v", event, top.kind(), top.len)
+	}
+	top.count++
+	return nil
+}
+
+func (v *Validator) key(event, key string) error {
+	if len(v.stack) == 0 || !v.stack[len(v.stack)-1].object {
+		return fmt.Errorf("invalid %v('%v'): key outside of object", event, key)
+	}
+
+	top := &v.stack[len(v.stack)-1]
+	if top.key {
+		return fmt.Errorf("invalid %v('%v'): missing value for previous key", event, key)
+	}
+	top.key = true
+	return nil
+}
+
+func (v *Validator) finish(event string, object bool) error {
+	if len(v.stack) == 0 {
+		return fmt.Errorf("invalid %v: no open object or array", event)
+	}
+
+	top := &v.stack[len(v.stack)-1]
+	if top.object != object {
+		return fmt.Errorf("invalid %v: %v is still open", event, top.kind())
+	}
+	if top.key {
+		return fmt.Errorf("invalid %v: missing value for last key", event)
+	}
+	if top.len >= 0 && top.count != top.len {
+		return fmt.Errorf("invalid %v: %v declared with length %v, but has %v entries",
+			event, top.kind(), top.len, top.count)
+	}
+
+	v.stack = v.stack[:len(v.stack)-1]
+	if len(v.stack) == 0 {
+		v.done = true
+	}
+	return nil
+}
+
+func (f *validatorFrame) kind() string {
+	if f.object {
+		return "object"
+	}
+	return "array"
+}
+
+func (v *Validator) OnNil() error {
+	if err := v.value("OnNil"); err != nil {
+		return err
+	}
+	return v.active.OnNil()
+}
+
+func (v *Validator) OnBool(b bool) error {
+	if err := v.value("OnBool"); err != nil {
+		return err
+	}
+	return v.active.OnBool(b)
+}
+
+func (v *Validator) OnString(s string) error {
+	if err := v.value("OnString"); err != nil {
+		return err
+	}
+	return v.active.OnString(s)
+}
+
+func (v *Validator) OnStringRef(s []byte) error {
+	if err := v.value("OnStringRef"); err != nil {
+		return err
+	}
+	return v.active.OnStringRef(s)
+}
+
+func (v *Validator) OnInt8(i int8) error {
+	if err := v.value("OnInt8"); err != nil {
+		return err
+	}
+	return v.active.OnInt8(i)
+}
+
+func (v *Validator) OnInt16(i int16) error {
+	if err := v.value("OnInt16"); err != nil {
+		return err
+	}
+	return v.active.OnInt16(i)
+}
+
+func (v *Validator) OnInt32(i int32) error {
+	if err := v.value("OnInt32"); err != nil {
+		return err
+	}
+	return v.active.OnInt32(i)
+}
+
+func (v *Validator) OnInt64(i int64) error {
+	if err := v.value("OnInt64"); err != nil {
+		return err
+	}
+	return v.active.OnInt64(i)
+}
+
+func (v *Validator) OnInt(i int) error {
+	if err := v.value("OnInt"); err != nil {
+		return err
+	}
+	return v.active.OnInt(i)
+}
+
+func (v *Validator) OnByte(b byte) error {
+	if err := v.value("OnByte"); err != nil {
+		return err
+	}
+	return v.active.OnByte(b)
+}
+
+func (v *Validator) OnUint8(u uint8) error {
+	if err := v.value("OnUint8"); err != nil {
+		return err
+	}
+	return v.active.OnUint8(u)
+}
+
+func (v *Validator) OnUint16(u uint16) error {
+	if err := v.value("OnUint16"); err != nil {
+		return err
+	}
+	return v.active.OnUint16(u)
+}
+
+func (v *Validator) OnUint32(u uint32) error {
+	if err := v.value("OnUint32"); err != nil {
+		return err
+	}
+	return v.active.OnUint32(u)
+}
+
+func (v *Validator) OnUint64(u uint64) error {
+	if err := v.value("OnUint64"); err != nil {
+		return err
+	}
+	return v.active.OnUint64(u)
+}
+
+func (v *Validator) OnUint(u uint) error {
+	if err := v.value("OnUint"); err != nil {
+		return err
+	}
+	return v.active.OnUint(u)
+}
+
+func (v *Validator) OnFloat32(f float32) error {
+	if err := v.value("OnFloat32"); err != nil {
+		return err
+	}
+	return v.active.OnFloat32(f)
+}
+
+func (v *Validator) OnFloat64(f float64) error {
+	if err := v.value("OnFloat64"); err != nil {
+		return err
+	}
+	return v.active.OnFloat64(f)
+}
+
+func (v *Validator) OnBoolArray(a []bool) error {
+	if err := v.value("OnBoolArray"); err != nil {
+		return err
+	}
+	return v.active.OnBoolArray(a)
+}
+
+func (v *Validator) OnStringArray(a []string) error {
+	if err := v.value("OnStringArray"); err != nil {
+		return err
+	}
+	return v.active.OnStringArray(a)
+}
+
+func (v *Validator) OnInt8Array(a []int8) error {
+	if err := v.value("OnInt8Array"); err != nil {
+		return err
+	}
+	return v.active.OnInt8Array(a)
+}
+
+func (v *Validator) OnInt16Array(a []int16) error {
+	if err := v.value("OnInt16Array"); err != nil {
+		return err
+	}
+	return v.active.OnInt16Array(a)
+}
+
+func (v *Validator) OnInt32Array(a []int32) error {
+	if err := v.value("OnInt32Array"); err != nil {
+		return err
+	}
+	return v.active.OnInt32Array(a)
+}
+
+func (v *Validator) OnInt64Array(a []int64) error {
+	if err := v.value("OnInt64Array"); err != nil {
+		return err
+	}
+	return v.active.OnInt64Array(a)
+}
+
+func (v *Validator) OnIntArray(a []int) error {
+	if err := v.value("OnIntArray"); err != nil {
+		return err
+	}
+	return v.active.OnIntArray(a)
+}
+
+func (v *Validator) OnBytes(b []byte) error {
+	if err := v.value("OnBytes"); err != nil {
+		return err
+	}
+	return v.active.OnBytes(b)
+}
+
+func (v *Validator) OnUint8Array(a []uint8) error {
+	if err := v.value("OnUint8Array"); err != nil {
+		return err
+	}
+	return v.active.OnUint8Array(a)
+}
+
+func (v *Validator) OnUint16Array(a []uint16) error {
+	if err := v.value("OnUint16Array"); err != nil {
+		return err
+	}
+	return v.active.OnUint16Array(a)
+}
+
+func (v *Validator) OnUint32Array(a []uint32) error {
+	if err := v.value("OnUint32Array"); err != nil {
+		return err
+	}
+	return v.active.OnUint32Array(a)
+}
+
+func (v *Validator) OnUint64Array(a []uint64) error {
+	if err := v.value("OnUint64Array"); err != nil {
+		return err
+	}
+	return v.active.OnUint64Array(a)
+}
+
+func (v *Validator) OnUintArray(a []uint) error {
+	if err := v.value("OnUintArray"); err != nil {
+		return err
+	}
+	return v.active.OnUintArray(a)
+}
+
+func (v *Validator) OnFloat32Array(a []float32) error {
+	if err := v.value("OnFloat32Array"); err != nil {
+		return err
+	}
+	return v.active.OnFloat32Array(a)
+}
+
+func (v *Validator) OnFloat64Array(a []float64) error {
+	if err := v.value("OnFloat64Array"); err != nil {
+		return err
+	}
+	return v.active.OnFloat64Array(a)
+}
+
+func (v *Validator) OnBoolObject(m map[string]bool) error {
+	if err := v.value("OnBoolObject"); err != nil {
+		return err
+	}
+	return v.active.OnBoolObject(m)
+}
+
+func (v *Validator) OnStringObject(m map[string]string) error {
+	if err := v.value("OnStringObject"); err != nil {
+		return err
+	}
+	return v.active.OnStringObject(m)
+}
+
+func (v *Validator) OnInt8Object(m map[string]int8) error {
+	if err := v.value("OnInt8Object"); err != nil {
+		return err
+	}
+	return v.active.OnInt8Object(m)
+}
+
+func (v *Validator) OnInt16Object(m map[string]int16) error {
+	if err := v.value("OnInt16Object"); err != nil {
+		return err
+	}
+	return v.active.OnInt16Object(m)
+}
+
+func (v *Validator) OnInt32Object(m map[string]int32) error {
+	if err := v.value("OnInt32Object"); err != nil {
+		return err
+	}
+	return v.active.OnInt32Object(m)
+}
+
+func (v *Validator) OnInt64Object(m map[string]int64) error {
+	if err := v.value("OnInt64Object"); err != nil {
+		return err
+	}
+	return v.active.OnInt64Object(m)
+}
+
+func (v *Validator) OnIntObject(m map[string]int) error {
+	if err := v.value("OnIntObject"); err != nil {
+		return err
+	}
+	return v.active.OnIntObject(m)
+}
+
+func (v *Validator) OnUint8Object(m map[string]uint8) error {
+	if err := v.value("OnUint8Object"); err != nil {
+		return err
+	}
+	return v.active.OnUint8Object(m)
+}
+
+func (v *Validator) OnUint16Object(m map[string]uint16) error {
+	if err := v.value("OnUint16Object"); err != nil {
+		return err
+	}
+	return v.active.OnUint16Object(m)
+}
+
+func (v *Validator) OnUint32Object(m map[string]uint32) error {
+	if err := v.value("OnUint32Object"); err != nil {
+		return err
+	}
+	return v.active.OnUint32Object(m)
+}
+
+func (v *Validator) OnUint64Object(m map[string]uint64) error {
+	if err := v.value("OnUint64Object"); err != nil {
+		return err
+	}
+	return v.active.OnUint64Object(m)
+}
+
+func (v *Validator) OnUintObject(m map[string]uint) error {
+	if err := v.value("OnUintObject"); err != nil {
+		return err
+	}
+	return v.active.OnUintObject(m)
+}
+
+func (v *Validator) OnFloat32Object(m map[string]float32) error {
+	if err := v.value("OnFloat32Object"); err != nil {
+		return err
+	}
+	return v.active.OnFloat32Object(m)
+}
+
+func (v *Validator) OnFloat64Object(m map[string]float64) error {
+	if err := v.value("OnFloat64Object"); err != nil {
+		return err
+	}
+	return v.active.OnFloat64Object(m)
+}
@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package visitors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+	"github.com/elastic/go-structform/sftest"
+)
+
+func TestValidatorValid(t *testing.T) {
+	tests := map[string]sftest.Recording{
+		"primitive":    {sftest.StringRec{"test"}},
+		"empty object": sftest.Obj(0, structform.AnyType),
+		"nested": sftest.Obj(2, structform.AnyType,
+			"a", sftest.Arr(-1, structform.AnyType, sftest.IntRec{1}, sftest.Obj(-1, structform.AnyType)),
+			"b", sftest.StringArrRec{[]string{"x"}},
+		),
+		"unknown length": sftest.Arr(-1, structform.AnyType,
+			sftest.NilRec{}, sftest.BoolRec{true}, sftest.Float64Rec{1.5},
+		),
+	}
+
+	for name, rec := range tests {
+		rec := rec
+		t.Run(name, func(t *testing.T) {
+			var got sftest.Recording
+			v := NewValidator(&got)
+			if err := rec.Replay(v); err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, v.Done())
+		})
+	}
+}
+
+func TestValidatorInvalid(t *testing.T) {
+	tests := map[string]struct {
+		rec  sftest.Recording
+		want string
+	}{
+		"key outside of object": {
+			rec:  sftest.Recording{sftest.ObjectKeyRec{"a"}},
+			want: "invalid OnKey('a'): key outside of object",
+		},
+		"key in array": {
+			rec:  sftest.Recording{sftest.ArrayStartRec{-1, structform.AnyType}, sftest.ObjectKeyRec{"a"}},
+			want: "invalid OnKey('a'): key outside of object",
+		},
+		"value without key": {
+			rec:  sftest.Recording{sftest.ObjectStartRec{-1, structform.AnyType}, sftest.IntRec{1}},
+			want: "invalid OnInt: object value without key",
+		},
+		"two keys": {
+			rec: sftest.Recording{
+				sftest.ObjectStartRec{-1, structform.AnyType},
+				sftest.ObjectKeyRec{"a"},
+				sftest.ObjectKeyRec{"b"},
+			},
+			want: "invalid OnKey('b'): missing value for previous key",
+		},
+		"two values": {
+			rec: sftest.Recording{
+				sftest.ObjectStartRec{-1, structform.AnyType},
+				sftest.ObjectKeyRec{"a"},
+				sftest.IntRec{1},
+				sftest.IntRec{2},
+			},
+			want: "invalid OnInt: object value without key",
+		},
+		"missing value at end": {
+			rec: sftest.Recording{
+				sftest.ObjectStartRec{-1, structform.AnyType},
+				sftest.ObjectKeyRec{"a"},
+				sftest.ObjectFinishRec{},
+			},
+			want: "invalid OnObjectFinished: missing value for last key",
+		},
+		"unbalanced": {
+			rec: sftest.Recording{
+				sftest.ObjectStartRec{-1, structform.AnyType},
+				sftest.ObjectKeyRec{"a"},
+				sftest.ArrayStartRec{-1, structform.AnyType},
+				sftest.ObjectFinishRec{},
+			},
+			want: "invalid OnObjectFinished: array is still open",
+		},
+		"finish without start": {
+			rec:  sftest.Recording{sftest.ArrayFinishRec{}},
+			want: "invalid OnArrayFinished: no open object or array",
+		},
+		"too few elements": {
+			rec:  sftest.Arr(2, structform.AnyType, sftest.IntRec{1}),
+			want: "invalid OnArrayFinished: array declared with length 2, but has 1 entries",
+		},
+		"too many fields": {
+			rec:  sftest.Obj(1, structform.AnyType, "a", sftest.IntRec{1}, "b", sftest.IntRec{2}),
+			want: "invalid OnInt: object exceeds declared length 1",
+		},
+		"second document": {
+			rec:  sftest.Recording{sftest.IntRec{1}, sftest.IntRec{2}},
+			want: "invalid OnInt: document already complete",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			err := test.rec.Replay(NewValidator(NilVisitor()))
+			if assert.Error(t, err) {
+				assert.Equal(t, test.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidatorStopsInvalidEvents(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	v := NewValidator(json.NewVisitor(buf))
+
+	assert.NoError(t, v.OnObjectStart(-1, structform.AnyType))
+	assert.Error(t, v.OnString("value"))
+	assert.NoError(t, v.OnKey("key"))
+	assert.NoError(t, v.OnString("value"))
+	assert.NoError(t, v.OnObjectFinished())
+	assert.True(t, v.Done())
+	assert.Equal(t, `{"key":"value"}`, buf.String())
+
+	v.Reset()
+	assert.False(t, v.Done())
+}